@@ -2,11 +2,13 @@ package uv
 
 import (
 	"bytes"
+	"fmt"
 	"image"
 	"io"
 	"strings"
 
 	"github.com/charmbracelet/x/ansi"
+	"github.com/clipperhouse/uax29/v2/graphemes"
 )
 
 // Position represents a position in a coordinate system.
@@ -97,6 +99,23 @@ func (l Line) Set(x int, c *Cell) {
 	}
 }
 
+// clipWide blanks the wide cell at the end of the line that doesn't fit in
+// it anymore, if any.
+func (l Line) clipWide() {
+	for x := len(l) - 1; x >= 0 && x >= len(l)-4; x-- {
+		if w := l[x].Width; w > 0 {
+			if x+w > len(l) {
+				blank := l[x]
+				blank.Empty()
+				for i := x; i < len(l); i++ {
+					l[i] = blank
+				}
+			}
+			return
+		}
+	}
+}
+
 // At returns the cell at the given x position.
 // If the cell does not exist, it returns nil.
 func (l Line) At(x int) *Cell {
@@ -107,6 +126,44 @@ func (l Line) At(x int) *Cell {
 	return &l[x]
 }
 
+// Slice returns a copy of the cells between the visual columns start
+// (inclusive) and end (exclusive). The columns are clamped to the line width.
+//
+// A wide cell that straddles either boundary is replaced with blank cells
+// covering its part within the range, keeping the cell style and link.
+func (l Line) Slice(start, end int) Line {
+	start = max(start, 0)
+	end = min(end, len(l))
+	if start >= end {
+		return Line{}
+	}
+
+	s := make(Line, end-start)
+	copy(s, l[start:end])
+
+	// Find cells that straddle the boundaries.
+	for x := start - 1; x >= 0; x-- {
+		if w := l[x].Width; w > 0 {
+			for i := start; i < x+w && i < end; i++ {
+				s[i-start] = l[x]
+				s[i-start].Empty()
+			}
+			break
+		}
+	}
+	for x := end - 1; x >= start; x-- {
+		if w := l[x].Width; w > 0 {
+			for i := x; i < end && x+w > end; i++ {
+				s[i-start] = l[x]
+				s[i-start].Empty()
+			}
+			break
+		}
+	}
+
+	return s
+}
+
 // String returns the string representation of the line. Any trailing spaces
 // are removed.
 func (l Line) String() string {
@@ -272,6 +329,39 @@ func (b *Buffer) Render() string {
 	return Lines(b.Lines).Render()
 }
 
+// MarshalANSI returns the buffer contents as text annotated with SGR and
+// hyperlink escape codes, one line per row separated by newlines. Styles and
+// links are reset at the end of each line. Use [ParseBuffer] to load the
+// result back into a buffer.
+func (b *Buffer) MarshalANSI() []byte {
+	return []byte(b.Render())
+}
+
+// ParseBuffer parses text annotated with SGR and hyperlink escape codes, like
+// the output of [Buffer.MarshalANSI], into a new buffer of the given width.
+// Each line of the text becomes a row of the buffer, and a single trailing
+// newline is ignored. Lines wider than the buffer are truncated, and shorter
+// lines are padded with empty cells.
+//
+// Cell widths are calculated using [ansi.WcWidth]. When width is zero, the
+// width of the widest line is used.
+func ParseBuffer(data []byte, width int) (*Buffer, error) {
+	if width < 0 {
+		return nil, fmt.Errorf("invalid buffer width: %d", width)
+	}
+
+	str := strings.ReplaceAll(string(data), "\r\n", "\n")
+	str = strings.TrimSuffix(str, "\n")
+	ss := NewStyledString(str)
+	if width == 0 {
+		width = ss.WcWidth()
+	}
+
+	scr := NewScreenBuffer(width, ss.Height())
+	ss.Draw(scr, scr.Bounds())
+	return scr.Buffer, nil
+}
+
 // Line returns a pointer to the line at the given y position.
 // If the line does not exist, it returns nil.
 func (b *Buffer) Line(y int) Line {
@@ -290,6 +380,65 @@ func (b *Buffer) CellAt(x int, y int) *Cell {
 	return b.Lines[y].At(x)
 }
 
+// NextCellPos returns the position of the cell that follows the one at the
+// given position. Each cell holds a whole grapheme cluster, including any
+// combining marks, and wide cells are skipped as a whole along with their
+// zero-width placeholder cells. At the end of a line, it moves to the first
+// cell of the next line.
+//
+// It returns the given position unchanged when it's the last cell of the
+// buffer or out of bounds.
+func (b *Buffer) NextCellPos(x, y int) (int, int) {
+	line := b.Line(y)
+	if x < 0 || x >= len(line) {
+		return x, y
+	}
+
+	nx := x + max(line[x].Width, 1)
+	for nx < len(line) && line[nx].Width == 0 {
+		nx++
+	}
+	if nx < len(line) {
+		return nx, y
+	}
+	if y+1 < len(b.Lines) {
+		return 0, y + 1
+	}
+	return x, y
+}
+
+// PrevCellPos returns the position of the cell that precedes the one at the
+// given position. Like [Buffer.NextCellPos], it skips over the zero-width
+// placeholder cells of wide cells and lands on their first cell. At the start
+// of a line, it moves to the last cell of the previous line.
+//
+// It returns the given position unchanged when it's the first cell of the
+// buffer or out of bounds.
+func (b *Buffer) PrevCellPos(x, y int) (int, int) {
+	line := b.Line(y)
+	if x < 0 || x >= len(line) {
+		return x, y
+	}
+
+	if x == 0 {
+		if y == 0 {
+			return x, y
+		}
+		y--
+		line = b.Lines[y]
+		x = len(line)
+		if x == 0 {
+			return 0, y
+		}
+	}
+
+	px := x - 1
+	for px > 0 && line[px].Width == 0 {
+		px--
+	}
+	return px, y
+}
+
 // SetCell sets the cell at the given x, y position.
 func (b *Buffer) SetCell(x, y int, c *Cell) {
 	if y < 0 || y >= len(b.Lines) {
@@ -299,6 +448,47 @@ func (b *Buffer) SetCell(x, y int, c *Cell) {
 	b.Lines[y].Set(x, c)
 }
 
+// SetString writes the string s starting at the given x, y position using
+// the given style. The string is split into grapheme clusters, so combining
+// marks stay in the same cell as their base character, and wide characters
+// take a cell followed by zero-width placeholder cells. Widths are measured
+// using [ansi.GraphemeWidth].
+//
+// The string is written on a single line and clipped at the edges of the
+// buffer. Control characters, such as newlines, and escape sequences are not
+// interpreted and are skipped. Use [StyledString] to draw styled or
+// multi-line text.
+func (b *Buffer) SetString(x, y int, s string, style Style) {
+	setString(b.SetCell, ansi.GraphemeWidth, b.Width(), x, y, s, style)
+}
+
+// setString writes the grapheme clusters of s to a line of the given width
+// using set. See [Buffer.SetString].
+func setString(set func(x, y int, c *Cell), m WidthMethod, width, x, y int, s string, style Style) {
+	grs := graphemes.FromString(s)
+	for grs.Next() && x < width {
+		gr := grs.Value()
+		w := m.StringWidth(gr)
+		if w == 0 {
+			// Control characters or stray zero-width characters.
+			continue
+		}
+
+		c := &Cell{Content: gr, Width: w, Style: style}
+		if x < 0 {
+			// The cell straddles or is past the left edge, only blank its
+			// visible columns.
+			c.Empty()
+			for i := 0; i < x+w; i++ {
+				set(i, y, c)
+			}
+		} else {
+			set(x, y, c)
+		}
+		x += w
+	}
+}
+
 // Height implements Screen.
 func (b *Buffer) Height() int {
 	return len(b.Lines)
@@ -319,18 +509,36 @@ func (b *Buffer) Bounds() Rectangle {
 	return Rect(0, 0, b.Width(), b.Height())
 }
 
-// Resize resizes the buffer to the given width and height.
+// Resize resizes the buffer to the given width and height. Existing content
+// is kept anchored to the top-left corner, and new cells are filled with
+// [EmptyCell]. See [Buffer.ResizeWithFill].
 func (b *Buffer) Resize(width int, height int) {
+	b.ResizeWithFill(width, height, nil)
+}
+
+// ResizeWithFill resizes the buffer to the given width and height, filling
+// the newly exposed cells with the given cell. Existing content is kept
+// anchored to the top-left corner, and content that falls outside of the new
+// size is dropped. A wide cell that would be cut by the new right edge is
+// replaced with blank cells that keep its style and link.
+//
+// If fill is nil or is a wide cell, [EmptyCell] is used instead.
+func (b *Buffer) ResizeWithFill(width int, height int, fill *Cell) {
 	curWidth, curHeight := b.Width(), b.Height()
 	if curWidth == width && curHeight == height {
 		// No need to resize if the dimensions are the same.
 		return
 	}
 
+	c := EmptyCell
+	if fill != nil && fill.Width == 1 {
+		c = *fill
+	}
+
 	if width > curWidth {
 		line := make(Line, width-curWidth)
 		for i := range line {
-			line[i] = EmptyCell
+			line[i] = c
 		}
 		for i := range b.Lines {
 			b.Lines[i] = append(b.Lines[i], line...)
@@ -338,6 +546,7 @@ func (b *Buffer) Resize(width int, height int) {
 	} else if width < curWidth {
 		for i := range b.Lines {
 			b.Lines[i] = b.Lines[i][:width]
+			b.Lines[i].clipWide()
 		}
 	}
 
@@ -345,7 +554,7 @@ func (b *Buffer) Resize(width int, height int) {
 		for i := len(b.Lines); i < height; i++ {
 			line := make(Line, width)
 			for j := range line {
-				line[j] = EmptyCell
+				line[j] = c
 			}
 			b.Lines = append(b.Lines, line)
 		}
@@ -410,11 +619,93 @@ func (b *Buffer) CloneArea(area Rectangle) *Buffer {
 	return n
 }
 
+// CopyArea copies the cells of the buffer within the source area into the
+// destination buffer, with the top-left corner of the area at the given
+// destination point. The copy is clipped to the bounds of both buffers. Wide
+// cells that straddle the edges of the copied area are replaced with blank
+// cells keeping their style and link.
+//
+// It's useful to composite buffers on top of each other, or to take and
+// restore snapshots of parts of a buffer.
+func (b *Buffer) CopyArea(dst *Buffer, dstPt image.Point, srcArea Rectangle) {
+	// Clip the source area to both buffers.
+	src := srcArea.Intersect(b.Bounds())
+	offset := dstPt.Sub(srcArea.Min)
+	src = src.Intersect(dst.Bounds().Sub(offset))
+	if src.Empty() {
+		return
+	}
+
+	// Slice the lines before copying in case both buffers are the same and
+	// the areas overlap.
+	lines := make([]Line, 0, src.Dy())
+	for y := src.Min.Y; y < src.Max.Y; y++ {
+		lines = append(lines, b.Lines[y].Slice(src.Min.X, src.Max.X))
+	}
+
+	for j, line := range lines {
+		for i := range line {
+			c := &line[i]
+			if c.IsZero() {
+				// Placeholder cells are set along with their wide cell.
+				continue
+			}
+			dst.SetCell(src.Min.X+i+offset.X, src.Min.Y+j+offset.Y, c)
+		}
+	}
+}
+
 // Clone clones the entire buffer into a new buffer.
 func (b *Buffer) Clone() *Buffer {
 	return b.CloneArea(b.Bounds())
 }
 
+// CellChange is a single cell that differs between two buffers. See
+// [Buffer.Diff].
+type CellChange struct {
+	// X and Y are the position of the cell.
+	X, Y int
+	// Cell is the new cell at the position. It is nil when the position is
+	// outside of the new buffer.
+	Cell *Cell
+}
+
+// Diff returns the list of cells that differ between the buffer and the other
+// buffer, in row-major order. The cell of each change is a copy of the cell in
+// the other buffer, which means applying the changes to the buffer makes it
+// look like the other buffer. The zero-width placeholder cells following wide
+// cells are not reported, since setting a wide cell covers them.
+//
+// Buffers are meant to be the same size. When they are not, every position of
+// either buffer is compared, and positions that only exist in the buffer are
+// reported with a nil [CellChange.Cell].
+func (b *Buffer) Diff(other *Buffer) []CellChange {
+	var changes []CellChange
+	height := max(b.Height(), other.Height())
+	width := max(b.Width(), other.Width())
+	for y := range height {
+		for x := range width {
+			oldc, newc := b.CellAt(x, y), other.CellAt(x, y)
+			switch {
+			case oldc == nil && newc == nil:
+				continue
+			case oldc != nil && newc != nil && oldc.Equal(newc):
+				continue
+			case newc != nil && newc.Width == 0:
+				// Placeholder cells are covered by the wide cell before
+				// them.
+				continue
+			}
+			change := CellChange{X: x, Y: y}
+			if newc != nil {
+				change.Cell = newc.Clone()
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
 // Draw draws the buffer to the given screen at the specified area.
 // It implements the [Drawable] interface.
 func (b *Buffer) Draw(scr Screen, area Rectangle) {
@@ -450,7 +741,6 @@ func (b *Buffer) Draw(scr Screen, area Rectangle) {
 // specified, it inserts lines in the entire buffer. Only cells within the
 // rectangle's horizontal bounds are affected. Lines are pushed out of the
 // rectangle bounds and lost. This follows terminal [ansi.IL] behavior.
-// It returns the pushed out lines.
 func (b *Buffer) InsertLine(y, n int, c *Cell) {
 	b.InsertLineArea(y, n, c, b.Bounds())
 }
@@ -459,7 +749,12 @@ func (b *Buffer) InsertLine(y, n int, c *Cell) {
 // given optional cell, within the rectangle bounds. Only cells within the
 // rectangle's horizontal bounds are affected. Lines are pushed out of the
 // rectangle bounds and lost. This follows terminal [ansi.IL] behavior.
+//
+// The rectangle is clipped to the buffer bounds. Nothing happens when y is
+// outside of the rectangle or n is not positive, and n is limited to the
+// lines left below y.
 func (b *Buffer) InsertLineArea(y, n int, c *Cell, area Rectangle) {
+	area = area.Intersect(b.Bounds())
 	if n <= 0 || y < area.Min.Y || y >= area.Max.Y || y >= b.Height() {
 		return
 	}
@@ -490,7 +785,12 @@ func (b *Buffer) InsertLineArea(y, n int, c *Cell, area Rectangle) {
 // rectangle's bounds are affected. Lines are shifted up within the bounds and
 // new blank lines are created at the bottom. This follows terminal [ansi.DL]
 // behavior.
+//
+// The rectangle is clipped to the buffer bounds. Nothing happens when y is
+// outside of the rectangle or n is not positive, and n is limited to the
+// lines left below y.
 func (b *Buffer) DeleteLineArea(y, n int, c *Cell, area Rectangle) {
+	area = area.Intersect(b.Bounds())
 	if n <= 0 || y < area.Min.Y || y >= area.Max.Y || y >= b.Height() {
 		return
 	}
@@ -622,6 +922,13 @@ func (s ScreenBuffer) WidthMethod() WidthMethod {
 	return s.Method
 }
 
+// SetString writes the string s starting at the given x, y position using
+// the given style. It's like [Buffer.SetString], but measures widths using
+// the screen width method.
+func (s ScreenBuffer) SetString(x, y int, str string, style Style) {
+	setString(s.SetCell, s.Method, s.Width(), x, y, str, style)
+}
+
 // TrimSpace trims trailing spaces from the end of each line in the given
 // string.
 func TrimSpace(s string) string {
@@ -717,6 +1024,13 @@ func (b *RenderBuffer) SetCell(x, y int, c *Cell) {
 	b.Buffer.SetCell(x, y, c)
 }
 
+// SetString writes the string s starting at the given x, y position using
+// the given style, and marks the changed cells as touched. See
+// [Buffer.SetString].
+func (b *RenderBuffer) SetString(x, y int, s string, style Style) {
+	setString(b.SetCell, ansi.GraphemeWidth, b.Width(), x, y, s, style)
+}
+
 // InsertLine inserts n lines at the given line position, with the given
 // optional cell, within the specified rectangles. If no rectangles are
 // specified, it inserts lines in the entire buffer. Only cells within the
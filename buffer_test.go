@@ -1,6 +1,8 @@
 package uv
 
 import (
+	"image"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -509,6 +511,41 @@ func TestBufferLineOperations(t *testing.T) {
 			t.Error("DeleteLineArea failed to move line up")
 		}
 	})
+
+	t.Run("OutOfBounds", func(t *testing.T) {
+		newBuf := func() *Buffer {
+			b := NewBuffer(3, 3)
+			for y, s := range []string{"A", "B", "C"} {
+				b.SetCell(0, y, &Cell{Content: s, Width: 1})
+			}
+			return b
+		}
+
+		cases := []struct {
+			name     string
+			op       func(b *Buffer)
+			expected string
+		}{
+			{"insert negative y", func(b *Buffer) { b.InsertLine(-1, 1, nil) }, "A\nB\nC"},
+			{"insert past the end", func(b *Buffer) { b.InsertLine(3, 1, nil) }, "A\nB\nC"},
+			{"insert zero lines", func(b *Buffer) { b.InsertLine(0, 0, nil) }, "A\nB\nC"},
+			{"insert too many lines", func(b *Buffer) { b.InsertLine(1, 10, nil) }, "A\n\n"},
+			{"delete negative y", func(b *Buffer) { b.DeleteLine(-1, 1, nil) }, "A\nB\nC"},
+			{"delete too many lines", func(b *Buffer) { b.DeleteLine(1, 10, nil) }, "A\n\n"},
+			{"insert area larger than buffer", func(b *Buffer) { b.InsertLineArea(0, 1, nil, Rect(-2, -2, 10, 10)) }, "\nA\nB"},
+			{"delete area larger than buffer", func(b *Buffer) { b.DeleteLineArea(0, 1, nil, Rect(-2, -2, 10, 10)) }, "B\nC\n"},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				b := newBuf()
+				tc.op(b)
+				if got := b.String(); got != tc.expected {
+					t.Errorf("expected %q, got %q", tc.expected, got)
+				}
+			})
+		}
+	})
 }
 
 // TestBufferCellOperations tests cell insertion and deletion
@@ -692,3 +729,399 @@ func width(s string) int {
 func height(s string) int {
 	return strings.Count(s, "\n") + 1
 }
+
+func TestLineSlice(t *testing.T) {
+	newLine := func() Line {
+		// "a你b好c" with wide cells at columns 1 and 4.
+		l := NewLine(7)
+		l.Set(0, &Cell{Content: "a", Width: 1})
+		l.Set(1, &Cell{Content: "你", Width: 2, Style: Style{Attrs: AttrBold}})
+		l.Set(3, &Cell{Content: "b", Width: 1})
+		l.Set(4, &Cell{Content: "好", Width: 2, Style: Style{Attrs: AttrItalic}})
+		l.Set(6, &Cell{Content: "c", Width: 1})
+		return l
+	}
+
+	tests := []struct {
+		name       string
+		start, end int
+		expected   string
+		width      int
+	}{
+		{name: "whole line", start: 0, end: 7, expected: "a你b好c", width: 7},
+		{name: "clamped", start: -3, end: 20, expected: "a你b好c", width: 7},
+		{name: "empty", start: 3, end: 3, expected: "", width: 0},
+		{name: "inverted", start: 5, end: 2, expected: "", width: 0},
+		{name: "wide at end boundary", start: 0, end: 2, expected: "a ", width: 2},
+		{name: "wide at start boundary", start: 2, end: 4, expected: " b", width: 2},
+		{name: "wide at both boundaries", start: 2, end: 5, expected: " b ", width: 3},
+		{name: "inside wide cell", start: 4, end: 5, expected: " ", width: 1},
+		{name: "wide cell fits", start: 3, end: 6, expected: "b好", width: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLine()
+			s := l.Slice(tt.start, tt.end)
+			if len(s) != tt.width {
+				t.Fatalf("expected width %d, got %d", tt.width, len(s))
+			}
+			var got strings.Builder
+			for _, c := range s {
+				got.WriteString(c.Content)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got.String())
+			}
+		})
+	}
+
+	// Straddling cells keep their style.
+	l := newLine()
+	s := l.Slice(2, 5)
+	if s[0].Style.Attrs != AttrBold || s[2].Style.Attrs != AttrItalic {
+		t.Errorf("expected blank cells to keep the wide cell style, got %v and %v", s[0].Style, s[2].Style)
+	}
+	// The original line is left untouched.
+	if l[1].Content != "你" || l[4].Content != "好" {
+		t.Errorf("expected original line to be unchanged, got %q", l.String())
+	}
+}
+
+func TestBufferCellPos(t *testing.T) {
+	// "a你é好" on the first line, with a combining accent, and "cd" on the
+	// second line.
+	buf := NewBuffer(6, 2)
+	buf.SetCell(0, 0, &Cell{Content: "a", Width: 1})
+	buf.SetCell(1, 0, &Cell{Content: "你", Width: 2})
+	buf.SetCell(3, 0, &Cell{Content: "e\u0301", Width: 1})
+	buf.SetCell(4, 0, &Cell{Content: "好", Width: 2})
+	buf.SetCell(0, 1, &Cell{Content: "c", Width: 1})
+	buf.SetCell(1, 1, &Cell{Content: "d", Width: 1})
+
+	cases := []struct {
+		name string
+		pos  Position
+		next Position
+		prev Position
+	}{
+		{name: "first cell", pos: Pos(0, 0), next: Pos(1, 0), prev: Pos(0, 0)},
+		{name: "wide cell", pos: Pos(1, 0), next: Pos(3, 0), prev: Pos(0, 0)},
+		{name: "placeholder", pos: Pos(2, 0), next: Pos(3, 0), prev: Pos(1, 0)},
+		{name: "combining mark", pos: Pos(3, 0), next: Pos(4, 0), prev: Pos(1, 0)},
+		{name: "wide cell at end of line", pos: Pos(4, 0), next: Pos(0, 1), prev: Pos(3, 0)},
+		{name: "start of line", pos: Pos(0, 1), next: Pos(1, 1), prev: Pos(4, 0)},
+		{name: "last cell", pos: Pos(5, 1), next: Pos(5, 1), prev: Pos(4, 1)},
+		{name: "out of bounds", pos: Pos(6, 0), next: Pos(6, 0), prev: Pos(6, 0)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if x, y := buf.NextCellPos(tc.pos.X, tc.pos.Y); Pos(x, y) != tc.next {
+				t.Errorf("expected next position %v, got %v", tc.next, Pos(x, y))
+			}
+			if x, y := buf.PrevCellPos(tc.pos.X, tc.pos.Y); Pos(x, y) != tc.prev {
+				t.Errorf("expected previous position %v, got %v", tc.prev, Pos(x, y))
+			}
+		})
+	}
+}
+
+func TestBufferSetString(t *testing.T) {
+	red := Style{Fg: ansi.Red}
+	cases := []struct {
+		name     string
+		x        int
+		s        string
+		expected Line
+	}{
+		{
+			name: "ascii",
+			x:    1,
+			s:    "ab",
+			expected: Line{
+				EmptyCell,
+				{Content: "a", Width: 1, Style: red},
+				{Content: "b", Width: 1, Style: red},
+				EmptyCell,
+				EmptyCell,
+			},
+		},
+		{
+			name: "wide and combining",
+			s:    "你e\u0301\n好",
+			expected: Line{
+				{Content: "你", Width: 2, Style: red},
+				{},
+				{Content: "e\u0301", Width: 1, Style: red},
+				{Content: "好", Width: 2, Style: red},
+				{},
+			},
+		},
+		{
+			name: "wide at right edge",
+			x:    2,
+			s:    "ab你c",
+			expected: Line{
+				EmptyCell,
+				EmptyCell,
+				{Content: "a", Width: 1, Style: red},
+				{Content: "b", Width: 1, Style: red},
+				{Content: " ", Width: 1, Style: red},
+			},
+		},
+		{
+			name: "left edge",
+			x:    -1,
+			s:    "你ab",
+			expected: Line{
+				{Content: " ", Width: 1, Style: red},
+				{Content: "a", Width: 1, Style: red},
+				{Content: "b", Width: 1, Style: red},
+				EmptyCell,
+				EmptyCell,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := NewBuffer(5, 1)
+			buf.SetString(tc.x, 0, tc.s, red)
+			if got := buf.Line(0); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected line %#v, got %#v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderBufferSetString(t *testing.T) {
+	buf := NewRenderBuffer(5, 2)
+	buf.SetString(1, 1, "ab", Style{})
+	if got := buf.TouchedLines(); got != 1 {
+		t.Fatalf("expected 1 touched line, got %d", got)
+	}
+	if ld := buf.Touched[1]; ld == nil || ld.FirstCell != 1 || ld.LastCell != 3 {
+		t.Errorf("expected cells 1 to 3 to be touched, got %+v", ld)
+	}
+}
+
+func TestBufferResizeWithFill(t *testing.T) {
+	red := Style{Fg: ansi.Red}
+	buf := NewBuffer(4, 2)
+	buf.SetString(0, 0, "ab你", red)
+	buf.SetString(0, 1, "cd", Style{})
+	fill := &Cell{Content: ".", Width: 1, Style: Style{Bg: ansi.Blue}}
+
+	buf.ResizeWithFill(6, 3, fill)
+	expected := "ab你..\ncd  ..\n......"
+	if got := buf.String(); got != expected {
+		t.Errorf("expected %q after growing, got %q", expected, got)
+	}
+	if c := buf.CellAt(5, 2); !c.Equal(fill) {
+		t.Errorf("expected fill cell, got %#v", c)
+	}
+
+	// The wide cell cut by the new edge is blanked.
+	buf.ResizeWithFill(3, 1, fill)
+	expected = "ab "
+	if got := buf.String(); got != expected {
+		t.Errorf("expected %q after shrinking, got %q", expected, got)
+	}
+	blank := Cell{Content: " ", Width: 1, Style: red}
+	if c := buf.CellAt(2, 0); !c.Equal(&blank) {
+		t.Errorf("expected blank cell %#v, got %#v", blank, c)
+	}
+
+	// Wide fill cells are ignored.
+	buf.ResizeWithFill(4, 1, &Cell{Content: "你", Width: 2})
+	if c := buf.CellAt(3, 0); !c.Equal(&EmptyCell) {
+		t.Errorf("expected empty cell, got %#v", c)
+	}
+}
+
+func TestBufferDiff(t *testing.T) {
+	a := NewBuffer(4, 2)
+	b := a.Clone()
+	if changes := a.Diff(b); len(changes) != 0 {
+		t.Fatalf("expected no changes between equal buffers, got %v", changes)
+	}
+
+	red := Style{Fg: ansi.Red}
+	b.SetCell(1, 0, &Cell{Content: "a", Width: 1})
+	b.SetCell(3, 1, &Cell{Content: " ", Width: 1, Style: red})
+	b.SetCell(0, 1, &Cell{Content: "你", Width: 2})
+
+	expected := []CellChange{
+		{X: 1, Y: 0, Cell: &Cell{Content: "a", Width: 1}},
+		{X: 0, Y: 1, Cell: &Cell{Content: "你", Width: 2}},
+		{X: 3, Y: 1, Cell: &Cell{Content: " ", Width: 1, Style: red}},
+	}
+	changes := a.Diff(b)
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("expected changes %v, got %v", expected, changes)
+	}
+
+	// The changes hold copies of the other buffer cells.
+	a.Diff(b)[0].Cell.Content = "b"
+	if c := b.CellAt(1, 0); c.Content != "a" {
+		t.Errorf("expected the buffer cell to be left untouched, got %q", c.Content)
+	}
+
+	// Applying the changes makes the buffers equal.
+	for _, c := range changes {
+		a.SetCell(c.X, c.Y, c.Cell)
+	}
+	if changes := a.Diff(b); len(changes) != 0 {
+		t.Errorf("expected no changes after applying the diff, got %v", changes)
+	}
+}
+
+func TestBufferDiffSize(t *testing.T) {
+	a := NewBuffer(2, 1)
+	b := NewBuffer(1, 2)
+
+	expected := []CellChange{
+		{X: 1, Y: 0},
+		{X: 0, Y: 1, Cell: &EmptyCell},
+	}
+	if changes := a.Diff(b); !reflect.DeepEqual(expected, changes) {
+		t.Errorf("expected changes %v, got %v", expected, changes)
+	}
+}
+
+func TestBufferMarshalANSI(t *testing.T) {
+	buf := NewBuffer(6, 3)
+	buf.SetCell(0, 0, &Cell{Content: "a", Width: 1, Style: Style{Fg: ansi.Red, Attrs: AttrBold}})
+	buf.SetCell(1, 0, &Cell{Content: "b", Width: 1, Link: NewLink("https://charm.sh")})
+	buf.SetCell(2, 1, &Cell{Content: "你", Width: 2, Style: Style{Bg: ansi.Blue}})
+	buf.SetCell(5, 2, &Cell{Content: " ", Width: 1, Style: Style{Bg: ansi.Green}})
+
+	data := buf.MarshalANSI()
+	if got := ansi.Strip(string(data)); got != "ab\n  你\n      " {
+		t.Errorf("expected plain text %q, got %q", "ab\n  你\n      ", got)
+	}
+
+	parsed, err := ParseBuffer(data, buf.Width())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Bounds() != buf.Bounds() {
+		t.Fatalf("expected bounds %v, got %v", buf.Bounds(), parsed.Bounds())
+	}
+	if changes := buf.Diff(parsed); len(changes) != 0 {
+		t.Errorf("expected the parsed buffer to match, got changes %v", changes)
+	}
+}
+
+func TestParseBuffer(t *testing.T) {
+	buf, err := ParseBuffer([]byte("\x1b[31mhello\x1b[m world\r\nfoo\n"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.Bounds(); got != Rect(0, 0, 11, 2) {
+		t.Errorf("expected bounds %v, got %v", Rect(0, 0, 11, 2), got)
+	}
+	if got := buf.String(); got != "hello world\nfoo" {
+		t.Errorf("expected %q, got %q", "hello world\nfoo", got)
+	}
+	if c := buf.CellAt(0, 0); c.Style.Fg != ansi.Red {
+		t.Errorf("expected a red cell, got %#v", c.Style)
+	}
+
+	buf, err = ParseBuffer([]byte("hello world"), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "hello" {
+		t.Errorf("expected the line to be truncated, got %q", got)
+	}
+
+	if _, err := ParseBuffer(nil, -1); err == nil {
+		t.Error("expected an error for a negative width")
+	}
+}
+
+func TestBufferCopyArea(t *testing.T) {
+	newSrc := func() *Buffer {
+		b := NewBuffer(4, 2)
+		for y, row := range []string{"abcd", "efgh"} {
+			for x, r := range row {
+				b.SetCell(x, y, &Cell{Content: string(r), Width: 1})
+			}
+		}
+		return b
+	}
+
+	cases := []struct {
+		name     string
+		dstPt    image.Point
+		srcArea  Rectangle
+		expected string
+	}{
+		{
+			name:     "inside",
+			dstPt:    image.Pt(1, 1),
+			srcArea:  Rect(1, 0, 2, 2),
+			expected: "\n bc\n fg",
+		},
+		{
+			name:     "clipped to destination",
+			dstPt:    image.Pt(3, 2),
+			srcArea:  Rect(0, 0, 4, 2),
+			expected: "\n\n   ab",
+		},
+		{
+			name:     "clipped to source",
+			dstPt:    image.Pt(0, 0),
+			srcArea:  Rect(-1, -1, 3, 3),
+			expected: "\n ab\n ef",
+		},
+		{
+			name:     "negative destination",
+			dstPt:    image.Pt(-2, -1),
+			srcArea:  Rect(0, 0, 4, 2),
+			expected: "gh\n\n",
+		},
+		{
+			name:     "outside",
+			dstPt:    image.Pt(10, 10),
+			srcArea:  Rect(0, 0, 4, 2),
+			expected: "\n\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := NewBuffer(5, 3)
+			newSrc().CopyArea(dst, tc.dstPt, tc.srcArea)
+			if got := dst.String(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBufferCopyAreaWideCells(t *testing.T) {
+	src := NewBuffer(4, 1)
+	src.SetCell(0, 0, &Cell{Content: "你", Width: 2, Style: Style{Fg: ansi.Red}})
+	src.SetCell(2, 0, &Cell{Content: "好", Width: 2})
+
+	dst := NewBuffer(4, 1)
+	src.CopyArea(dst, image.Pt(0, 0), Rect(1, 0, 2, 1))
+	expected := Line{
+		{Content: " ", Width: 1, Style: Style{Fg: ansi.Red}},
+		EmptyCell,
+		EmptyCell,
+		EmptyCell,
+	}
+	if !reflect.DeepEqual(expected, dst.Lines[0]) {
+		t.Errorf("expected %#v, got %#v", expected, dst.Lines[0])
+	}
+
+	// Overlapping copy within the same buffer.
+	src.CopyArea(src, image.Pt(2, 0), Rect(0, 0, 2, 1))
+	if got := src.String(); got != "你你" {
+		t.Errorf("expected %q, got %q", "你你", got)
+	}
+}
@@ -1,7 +1,9 @@
 package uv
 
 import (
+	"hash/fnv"
 	"image/color"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/colorprofile"
@@ -108,6 +110,41 @@ func (h *Link) IsZero() bool {
 	return *h == Link{}
 }
 
+// ID returns the value of the "id" parameter of the hyperlink, if any.
+func (h *Link) ID() string {
+	for _, p := range strings.Split(h.Params, ":") {
+		if id, ok := strings.CutPrefix(p, "id="); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// WithID returns a copy of the hyperlink with its "id" parameter set to the
+// given id, replacing any existing one.
+//
+// Terminals treat cells that share the same URL and id as a single hyperlink,
+// for example, to highlight all of them on hover when the link spans multiple
+// lines.
+func (h *Link) WithID(id string) Link {
+	params := []string{"id=" + id}
+	for _, p := range strings.Split(h.Params, ":") {
+		if p != "" && !strings.HasPrefix(p, "id=") {
+			params = append(params, p)
+		}
+	}
+	return Link{URL: h.URL, Params: strings.Join(params, ":")}
+}
+
+// NewLinkID returns a hyperlink id derived from the given seed. The same seed
+// always produces the same id, which keeps ids stable across frames so that
+// they don't show up as changes to the renderer.
+func NewLinkID(seed string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(seed))
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
 // These are the available text attributes that can be combined to create
 // different styles.
 const (
@@ -424,6 +461,25 @@ func (s *Style) IsZero() bool {
 	return *s == Style{}
 }
 
+// Inherit sets the unset properties of the style from base. Colors and the
+// underline style are only taken from base when they're unset, and the
+// attributes of base are added to the style's own.
+func (s *Style) Inherit(base Style) {
+	if s.Fg == nil {
+		s.Fg = base.Fg
+	}
+	if s.Bg == nil {
+		s.Bg = base.Bg
+	}
+	if s.UnderlineColor == nil {
+		s.UnderlineColor = base.UnderlineColor
+	}
+	if s.Underline == UnderlineNone {
+		s.Underline = base.Underline
+	}
+	s.Attrs |= base.Attrs
+}
+
 // ConvertStyle converts a style to respect the given color profile.
 func ConvertStyle(s Style, p colorprofile.Profile) Style {
 	switch p {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestConvertStyle(t *testing.T) {
@@ -747,3 +748,131 @@ func TestStyleDiff(t *testing.T) {
 		})
 	}
 }
+
+// applyStyle applies the SGR sequences in seq to the given style.
+func applyStyle(pen Style, seq string) Style {
+	p := ansi.GetParser()
+	defer ansi.PutParser(p)
+	var state byte
+	for len(seq) > 0 {
+		s, _, n, newState := ansi.DecodeSequence(seq, state, p)
+		if ansi.HasCsiPrefix(s) && p.Command() == 'm' {
+			ReadStyle(p.Params(), &pen)
+		}
+		state = newState
+		seq = seq[n:]
+	}
+	return pen
+}
+
+func TestStyleDiffAttributes(t *testing.T) {
+	// The base style keeps the diffs from resetting everything.
+	base := Style{Fg: ansi.Red}
+	with := func(attrs uint8, ul Underline) Style {
+		s := base
+		s.Attrs = attrs
+		s.Underline = ul
+		return s
+	}
+
+	tests := []struct {
+		name string
+		from Style
+		to   Style
+	}{
+		{"bold", base, with(AttrBold, UnderlineNone)},
+		{"faint", base, with(AttrFaint, UnderlineNone)},
+		{"italic", base, with(AttrItalic, UnderlineNone)},
+		{"slow blink", base, with(AttrBlink, UnderlineNone)},
+		{"rapid blink", base, with(AttrRapidBlink, UnderlineNone)},
+		{"reverse", base, with(AttrReverse, UnderlineNone)},
+		{"conceal", base, with(AttrConceal, UnderlineNone)},
+		{"strikethrough", base, with(AttrStrikethrough, UnderlineNone)},
+		{"single underline", base, with(0, UnderlineSingle)},
+		{"double underline", base, with(0, UnderlineDouble)},
+		{"curly underline", base, with(0, UnderlineCurly)},
+		{"bold to faint", with(AttrBold, UnderlineNone), with(AttrFaint, UnderlineNone)},
+		{"bold and faint to bold", with(AttrBold|AttrFaint, UnderlineNone), with(AttrBold, UnderlineNone)},
+		{"slow to rapid blink", with(AttrBlink, UnderlineNone), with(AttrRapidBlink, UnderlineNone)},
+		{"both blinks to slow", with(AttrBlink|AttrRapidBlink, UnderlineNone), with(AttrBlink, UnderlineNone)},
+		{"double to single underline", with(0, UnderlineDouble), with(0, UnderlineSingle)},
+		{"single to double underline", with(0, UnderlineSingle), with(0, UnderlineDouble)},
+		{"colored curly underline", base, Style{Fg: ansi.Red, Underline: UnderlineCurly, UnderlineColor: ansi.Red}},
+		{"underline color kept across styles", Style{Fg: ansi.Red, Underline: UnderlineCurly, UnderlineColor: ansi.Blue}, Style{Fg: ansi.Red, Underline: UnderlineDashed, UnderlineColor: ansi.Blue}},
+		{"underline color changed", Style{Fg: ansi.Red, Underline: UnderlineDotted, UnderlineColor: ansi.Blue}, Style{Fg: ansi.Red, Underline: UnderlineDotted, UnderlineColor: ansi.TrueColor(0xff8700)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, dir := range []struct{ from, to Style }{{tt.from, tt.to}, {tt.to, tt.from}} {
+				seq := StyleDiff(&dir.from, &dir.to)
+				if seq == "" {
+					t.Fatalf("expected a diff from %#v to %#v", dir.from, dir.to)
+				}
+				if got := applyStyle(dir.from, seq); !got.Equal(&dir.to) {
+					t.Errorf("diff %q from %#v: expected %#v, got %#v", seq, dir.from, dir.to, got)
+				}
+			}
+		})
+	}
+}
+
+func TestStyleInherit(t *testing.T) {
+	base := Style{Fg: ansi.Red, Bg: ansi.Blue, UnderlineColor: ansi.Green, Underline: UnderlineCurly, Attrs: AttrBold}
+	cases := []struct {
+		name     string
+		style    Style
+		expected Style
+	}{
+		{name: "empty", style: Style{}, expected: base},
+		{
+			name:     "own properties are kept",
+			style:    Style{Fg: ansi.Yellow, Underline: UnderlineSingle, Attrs: AttrItalic},
+			expected: Style{Fg: ansi.Yellow, Bg: ansi.Blue, UnderlineColor: ansi.Green, Underline: UnderlineSingle, Attrs: AttrBold | AttrItalic},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.style.Inherit(base)
+			if !tc.style.Equal(&tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, tc.style)
+			}
+		})
+	}
+}
+
+func TestLinkID(t *testing.T) {
+	tests := []struct {
+		name   string
+		link   Link
+		id     string
+		params string
+	}{
+		{name: "no params", link: NewLink("https://charm.sh"), id: "a", params: "id=a"},
+		{name: "replace id", link: NewLink("https://charm.sh", "id=b"), id: "a", params: "id=a"},
+		{name: "keep params", link: NewLink("https://charm.sh", "foo=bar", "id=b"), id: "a", params: "id=a:foo=bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.link.WithID(tt.id)
+			if l.Params != tt.params {
+				t.Errorf("expected params %q, got %q", tt.params, l.Params)
+			}
+			if l.ID() != tt.id {
+				t.Errorf("expected id %q, got %q", tt.id, l.ID())
+			}
+			if l.URL != tt.link.URL {
+				t.Errorf("expected url %q, got %q", tt.link.URL, l.URL)
+			}
+		})
+	}
+
+	if NewLinkID("foo") != NewLinkID("foo") {
+		t.Error("expected equal seeds to produce equal ids")
+	}
+	if NewLinkID("foo") == NewLinkID("bar") {
+		t.Error("expected different seeds to produce different ids")
+	}
+}
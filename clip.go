@@ -0,0 +1,76 @@
+package uv
+
+// Clip returns a [Drawable] that draws d as if the origin of the drawing area
+// were shifted by the given offset, and clips anything that falls outside of
+// the area. This can be used to scroll content that is larger than the
+// visible area, where offset is the scroll position of the content and size
+// is the size of the whole content.
+//
+// The child is drawn to an area of the given size that starts at
+// area.Min - offset. The size doesn't depend on the offset, so content that
+// lays out to its area, such as wrapped text, keeps the same layout while it
+// scrolls. A zero width or height uses the one of the area.
+//
+// A wide cell that straddles either the left or right edge of the area is
+// partially visible. In that case, the visible columns of the cell are
+// replaced with blank cells that keep the cell style and link.
+func Clip(d Drawable, offset Position, size Size) Drawable {
+	return DrawableFunc(func(scr Screen, area Rectangle) {
+		if d == nil || area.Empty() {
+			return
+		}
+		w, h := size.Width, size.Height
+		if w <= 0 {
+			w = area.Dx()
+		}
+		if h <= 0 {
+			h = area.Dy()
+		}
+		child := Rect(area.Min.X-offset.X, area.Min.Y-offset.Y, w, h)
+		d.Draw(&clipScreen{Screen: scr, clip: area}, child)
+	})
+}
+
+// clipScreen is a [Screen] that ignores any cells outside of its clip area.
+type clipScreen struct {
+	Screen
+	clip Rectangle
+}
+
+var _ Screen = (*clipScreen)(nil)
+
+// CellAt implements [Screen].
+func (s *clipScreen) CellAt(x, y int) *Cell {
+	if !Pos(x, y).In(s.clip) {
+		return nil
+	}
+	return s.Screen.CellAt(x, y)
+}
+
+// SetCell implements [Screen].
+func (s *clipScreen) SetCell(x, y int, c *Cell) {
+	if y < s.clip.Min.Y || y >= s.clip.Max.Y {
+		return
+	}
+
+	w := 1
+	if c != nil && c.Width > 1 {
+		w = c.Width
+	}
+	if x >= s.clip.Max.X || x+w <= s.clip.Min.X {
+		return
+	}
+
+	if x < s.clip.Min.X || x+w > s.clip.Max.X {
+		// The cell straddles the clip edge, fill its visible part with blank
+		// cells.
+		blank := *c
+		blank.Empty()
+		for i := max(x, s.clip.Min.X); i < min(x+w, s.clip.Max.X); i++ {
+			s.Screen.SetCell(i, y, &blank)
+		}
+		return
+	}
+
+	s.Screen.SetCell(x, y, c)
+}
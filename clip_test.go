@@ -0,0 +1,109 @@
+package uv
+
+import "testing"
+
+func TestClip(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		width    int
+		height   int
+		area     Rectangle
+		offset   Position
+		size     Size
+		expected []string
+	}{
+		{
+			name:   "no offset",
+			input:  "abcdef\nghijkl\nmnopqr",
+			width:  6,
+			height: 4,
+			area:   Rect(1, 1, 3, 2),
+			expected: []string{
+				"......",
+				".abc..",
+				".ghi..",
+				"......",
+			},
+		},
+		{
+			name:   "scroll both axes",
+			input:  "abcdef\nghijkl\nmnopqr",
+			width:  6,
+			height: 4,
+			area:   Rect(1, 1, 3, 2),
+			offset: Pos(2, 1),
+			size:   Size{Width: 6, Height: 3},
+			expected: []string{
+				"......",
+				".ijk..",
+				".opq..",
+				"......",
+			},
+		},
+		{
+			name:   "scroll past content",
+			input:  "abc",
+			width:  4,
+			height: 2,
+			area:   Rect(0, 0, 4, 2),
+			offset: Pos(1, 1),
+			size:   Size{Width: 3, Height: 1},
+			expected: []string{
+				"....",
+				"....",
+			},
+		},
+		{
+			name:   "wide cells straddling edges",
+			input:  "你好世界",
+			width:  5,
+			height: 1,
+			area:   Rect(0, 0, 4, 1),
+			offset: Pos(1, 0),
+			size:   Size{Width: 8, Height: 1},
+			expected: []string{
+				" 好 .",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := NewScreenBuffer(tc.width, tc.height)
+			buf.Fill(&Cell{Content: ".", Width: 1})
+			Clip(NewStyledString(tc.input), tc.offset, tc.size).Draw(buf, tc.area)
+			for y, want := range tc.expected {
+				if got := buf.Line(y).String(); got != want {
+					t.Errorf("expected line %d to be %q, got %q", y, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestClipWrapped(t *testing.T) {
+	// The content is laid out to its own size, so scrolling it horizontally
+	// shifts the view without changing where the lines wrap.
+	cases := []struct {
+		offset   Position
+		expected []string
+	}{
+		{offset: Pos(0, 0), expected: []string{"ab", "ef"}},
+		{offset: Pos(1, 0), expected: []string{"bc", "f"}},
+		{offset: Pos(2, 0), expected: []string{"cd", ""}},
+		{offset: Pos(2, 1), expected: []string{"", ""}},
+	}
+
+	ss := NewStyledString("abcdef")
+	ss.Wrap = true
+	for _, tc := range cases {
+		buf := NewScreenBuffer(2, 2)
+		Clip(ss, tc.offset, Size{Width: 4, Height: 2}).Draw(buf, buf.Bounds())
+		for y, want := range tc.expected {
+			if got := TrimSpace(buf.Line(y).String()); got != want {
+				t.Errorf("offset %v: expected line %d to be %q, got %q", tc.offset, y, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package border provides a component that draws a border, with an optional
+// title, around another component.
+package border
+
+import (
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/screen"
+)
+
+// Sides is a set of border sides.
+type Sides uint8
+
+// Border sides.
+const (
+	Top Sides = 1 << iota
+	Right
+	Bottom
+	Left
+
+	// AllSides is the set of all border sides.
+	AllSides = Top | Right | Bottom | Left
+)
+
+// Ellipsis is the tail used to truncate titles that don't fit in the top
+// edge of the border.
+const Ellipsis = "…"
+
+// Border is a component that draws a border around a child component.
+//
+// The border is drawn on the outer edges of the area, and the child is drawn
+// in the area inside the border. Sides can be hidden individually, in which
+// case the child takes their space. Corners are only drawn when both of their
+// adjacent sides are visible.
+type Border struct {
+	// Child is the component drawn inside the border. It can be nil.
+	Child uv.Drawable
+	// Set is the set of characters, styles, and links used to draw the
+	// border. Use [uv.Border.Style] to style all the sides at once.
+	Set uv.Border
+	// Hidden is the set of sides that are not drawn.
+	Hidden Sides
+	// Title is drawn on the top edge of the border, after the top-left
+	// corner. It can contain SGR and hyperlink escape codes. Titles that don't
+	// fit are truncated with an [Ellipsis]. The title is not drawn when the
+	// top side is hidden.
+	Title string
+	// TitleStyle is the base style of the title. Styles set by escape codes
+	// in [Border.Title] take precedence over it.
+	TitleStyle uv.Style
+}
+
+var _ uv.Drawable = (*Border)(nil)
+
+// New creates a new [Border] around the given child using the
+// [uv.NormalBorder] set.
+func New(child uv.Drawable) *Border {
+	return &Border{Child: child, Set: uv.NormalBorder()}
+}
+
+// Inset returns the area inside the border for the given outer area. This is
+// the area the child is drawn in.
+func (b *Border) Inset(area uv.Rectangle) uv.Rectangle {
+	if b.visible(Top) {
+		area.Min.Y++
+	}
+	if b.visible(Right) {
+		area.Max.X--
+	}
+	if b.visible(Bottom) {
+		area.Max.Y--
+	}
+	if b.visible(Left) {
+		area.Min.X++
+	}
+	if area.Empty() {
+		return uv.Rectangle{}
+	}
+	return area
+}
+
+// Draw draws the border on the edges of the given area, and the child inside
+// of it.
+func (b *Border) Draw(scr uv.Screen, area uv.Rectangle) {
+	if area.Empty() {
+		return
+	}
+
+	top, right, bottom, left := b.visible(Top), b.visible(Right), b.visible(Bottom), b.visible(Left)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			atTop := top && y == area.Min.Y
+			atBottom := bottom && y == area.Max.Y-1
+			atLeft := left && x == area.Min.X
+			atRight := right && x == area.Max.X-1
+
+			var side *uv.Side
+			switch {
+			case atTop && atLeft:
+				side = &b.Set.TopLeft
+			case atTop && atRight:
+				side = &b.Set.TopRight
+			case atBottom && atLeft:
+				side = &b.Set.BottomLeft
+			case atBottom && atRight:
+				side = &b.Set.BottomRight
+			case atTop:
+				side = &b.Set.Top
+			case atBottom:
+				side = &b.Set.Bottom
+			case atLeft:
+				side = &b.Set.Left
+			case atRight:
+				side = &b.Set.Right
+			default:
+				continue
+			}
+
+			if cell := uv.NewCell(scr.WidthMethod(), side.Content); cell != nil {
+				cell.Style = side.Style
+				cell.Link = side.Link
+				scr.SetCell(x, y, cell)
+			}
+		}
+	}
+
+	if top && b.Title != "" {
+		b.drawTitle(scr, area, left, right)
+	}
+
+	if b.Child != nil {
+		if inner := b.Inset(area); !inner.Empty() {
+			b.Child.Draw(scr, inner)
+		}
+	}
+}
+
+// drawTitle draws the title on the top edge, between the corners.
+func (b *Border) drawTitle(scr uv.Screen, area uv.Rectangle, left, right bool) {
+	edge := uv.Rect(area.Min.X, area.Min.Y, area.Dx(), 1)
+	if left {
+		edge.Min.X++
+	}
+	if right {
+		edge.Max.X--
+	}
+	if edge.Empty() {
+		return
+	}
+
+	title := uv.NewStyledString(b.Title)
+	w := scr.WidthMethod().StringWidth(b.Title)
+	if w > edge.Dx() {
+		w = edge.Dx()
+		title.Tail = Ellipsis
+	}
+	titleArea := uv.Rect(edge.Min.X, edge.Min.Y, w, 1)
+	title.Draw(scr, titleArea)
+
+	// Apply the title style, and the top side style, to the cells that don't
+	// define their own.
+	screen.InheritStyle(scr, titleArea, b.TitleStyle)
+	screen.InheritStyle(scr, titleArea, b.Set.Top.Style)
+}
+
+func (b *Border) visible(s Sides) bool {
+	return b.Hidden&s == 0
+}
@@ -0,0 +1,148 @@
+package border
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/screen"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// fill is a child component that fills its area with a character.
+type fill string
+
+func (f fill) Draw(scr uv.Screen, area uv.Rectangle) {
+	screen.FillArea(scr, &uv.Cell{Content: string(f), Width: 1}, area)
+}
+
+func TestBorder(t *testing.T) {
+	cases := []struct {
+		name     string
+		width    int
+		height   int
+		title    string
+		hidden   Sides
+		expected string
+	}{
+		{
+			name:   "all sides",
+			width:  6,
+			height: 3,
+			expected: "" +
+				"┌────┐\n" +
+				"│xxxx│\n" +
+				"└────┘",
+		},
+		{
+			name:   "title",
+			width:  8,
+			height: 3,
+			title:  "logs",
+			expected: "" +
+				"┌logs──┐\n" +
+				"│xxxxxx│\n" +
+				"└──────┘",
+		},
+		{
+			name:   "title fits exactly",
+			width:  6,
+			height: 3,
+			title:  "logs",
+			expected: "" +
+				"┌logs┐\n" +
+				"│xxxx│\n" +
+				"└────┘",
+		},
+		{
+			name:   "title truncated",
+			width:  6,
+			height: 3,
+			title:  "output",
+			expected: "" +
+				"┌out…┐\n" +
+				"│xxxx│\n" +
+				"└────┘",
+		},
+		{
+			name:   "hidden left and right",
+			width:  4,
+			height: 3,
+			hidden: Left | Right,
+			title:  "ab",
+			expected: "" +
+				"ab──\n" +
+				"xxxx\n" +
+				"────",
+		},
+		{
+			name:   "hidden top",
+			width:  4,
+			height: 3,
+			hidden: Top,
+			title:  "ab",
+			expected: "" +
+				"│xx│\n" +
+				"│xx│\n" +
+				"└──┘",
+		},
+		{
+			name:   "all hidden",
+			width:  2,
+			height: 2,
+			hidden: AllSides,
+			expected: "" +
+				"xx\n" +
+				"xx",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := New(fill("x"))
+			b.Title = tc.title
+			b.Hidden = tc.hidden
+
+			buf := uv.NewScreenBuffer(tc.width, tc.height)
+			b.Draw(buf, buf.Bounds())
+			if got := buf.String(); got != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBorderInset(t *testing.T) {
+	b := New(nil)
+	if got, want := b.Inset(uv.Rect(0, 0, 10, 5)), uv.Rect(1, 1, 8, 3); got != want {
+		t.Errorf("expected inset %v, got %v", want, got)
+	}
+	if got := b.Inset(uv.Rect(0, 0, 2, 2)); !got.Empty() {
+		t.Errorf("expected an empty inset, got %v", got)
+	}
+	b.Hidden = Top | Left
+	if got, want := b.Inset(uv.Rect(0, 0, 10, 5)), uv.Rect(0, 0, 9, 4); got != want {
+		t.Errorf("expected inset %v, got %v", want, got)
+	}
+}
+
+func TestBorderTitleStyle(t *testing.T) {
+	b := New(nil)
+	b.Set = b.Set.Style(uv.Style{Fg: ansi.Blue})
+	b.Title = "a\x1b[31mb"
+	b.TitleStyle = uv.Style{Attrs: uv.AttrBold}
+
+	buf := uv.NewScreenBuffer(5, 2)
+	b.Draw(buf, buf.Bounds())
+
+	expected := []uv.Style{
+		{Fg: ansi.Blue},
+		{Fg: ansi.Blue, Attrs: uv.AttrBold},
+		{Fg: ansi.Red, Attrs: uv.AttrBold},
+		{Fg: ansi.Blue},
+	}
+	for x, style := range expected {
+		if c := buf.CellAt(x, 0); !c.Style.Equal(&style) {
+			t.Errorf("expected cell %d style %#v, got %#v", x, style, c.Style)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package image provides a component that draws images using Sixel graphics.
+package image
+
+import (
+	"bytes"
+	stdimage "image"
+	"image/draw"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/screen"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/sixel"
+)
+
+// DefaultCellSize is the cell size in pixels used when [Image.CellSize] is
+// not set. Use the [uv.CellSizeEvent] reported by the terminal to get the real
+// cell size.
+var DefaultCellSize = uv.Size{Width: 10, Height: 20} //nolint:mnd
+
+// Mode is the way an image is scaled to fit its area.
+type Mode int
+
+// Scaling modes.
+const (
+	// ModeContain scales the image to fit inside the area while keeping its
+	// aspect ratio. Parts of the area may be left empty.
+	ModeContain Mode = iota
+	// ModeCover scales the image to cover the whole area while keeping its
+	// aspect ratio. The image is cropped around its center.
+	ModeCover
+	// ModeStretch scales the image to the exact size of the area, ignoring its
+	// aspect ratio.
+	ModeStretch
+)
+
+// Image is a component that draws an image using Sixel graphics. The image is
+// scaled to the area it's drawn in, and the encoded sequence is cached until
+// the area size, cell size, or mode change.
+//
+// The Sixel sequence is emitted in the top-left cell of the area, and the
+// cursor is moved back to the end of the first row after the image is drawn.
+// This relies on absolute cursor positioning, which means the image should be
+// drawn on a fullscreen, alternate screen buffer.
+type Image struct {
+	// CellSize is the size of a single terminal cell in pixels. It's used to
+	// compute the size of the area in pixels. When zero, [DefaultCellSize] is
+	// used.
+	CellSize uv.Size
+	// Mode is the way the image is scaled to fit its area.
+	Mode Mode
+
+	img stdimage.Image
+
+	// cache
+	key     cacheKey
+	encoded string
+	cols    int
+	rows    int
+}
+
+type cacheKey struct {
+	size     uv.Size
+	cellSize uv.Size
+	mode     Mode
+}
+
+var _ uv.Drawable = (*Image)(nil)
+
+// New creates a new [Image] component for the given image.
+func New(img stdimage.Image) *Image {
+	return &Image{img: img}
+}
+
+// SetImage changes the image of the component.
+func (i *Image) SetImage(img stdimage.Image) {
+	i.img = img
+	i.encoded = ""
+}
+
+// Image returns the image of the component.
+func (i *Image) Image() stdimage.Image {
+	return i.img
+}
+
+// Draw draws the image in the given area. Cells of the area that are not
+// covered by the image are cleared.
+func (i *Image) Draw(scr uv.Screen, area uv.Rectangle) {
+	if area.Empty() {
+		return
+	}
+
+	screen.FillArea(scr, &uv.EmptyCell, area)
+	if i.img == nil || i.img.Bounds().Empty() {
+		return
+	}
+
+	cellSize := i.CellSize
+	if cellSize.Width <= 0 || cellSize.Height <= 0 {
+		cellSize = DefaultCellSize
+	}
+
+	key := cacheKey{
+		size:     uv.Size{Width: area.Dx(), Height: area.Dy()},
+		cellSize: cellSize,
+		mode:     i.Mode,
+	}
+	if i.encoded == "" || i.key != key {
+		i.encode(key)
+	}
+	if i.encoded == "" {
+		return
+	}
+
+	scr.SetCell(area.Min.X, area.Min.Y, &uv.Cell{
+		Content: i.encoded +
+			ansi.CursorPosition(area.Min.X+i.cols+1, area.Min.Y+1),
+		Width: i.cols,
+	})
+	for y := 1; y < i.rows; y++ {
+		// Skip over the cells covered by the image.
+		scr.SetCell(area.Min.X, area.Min.Y+y, &uv.Cell{
+			Content: ansi.CursorForward(i.cols),
+			Width:   i.cols,
+		})
+	}
+}
+
+// encode scales the image and encodes it as a Sixel sequence.
+func (i *Image) encode(key cacheKey) {
+	i.key = key
+	i.encoded = ""
+
+	width := key.size.Width * key.cellSize.Width
+	height := key.size.Height * key.cellSize.Height
+	img := scale(i.img, width, height, key.mode)
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return
+	}
+
+	var buf bytes.Buffer
+	var enc sixel.Encoder
+	if err := enc.Encode(&buf, img); err != nil {
+		return
+	}
+
+	i.encoded = ansi.SixelGraphics(0, 1, 0, buf.Bytes())
+	i.cols = min(key.size.Width, ceilDiv(bounds.Dx(), key.cellSize.Width))
+	i.rows = min(key.size.Height, ceilDiv(bounds.Dy(), key.cellSize.Height))
+}
+
+// scale scales the image to the given size in pixels using nearest-neighbor
+// sampling.
+func scale(src stdimage.Image, width, height int, mode Mode) stdimage.Image {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	if width <= 0 || height <= 0 || sw <= 0 || sh <= 0 {
+		return stdimage.NewRGBA(stdimage.Rectangle{})
+	}
+
+	// r is the part of the source image that's sampled.
+	r := sb
+	switch mode {
+	case ModeContain:
+		if sw*height > sh*width {
+			height = max(1, sh*width/sw)
+		} else {
+			width = max(1, sw*height/sh)
+		}
+	case ModeCover:
+		if sw*height > sh*width {
+			cw := max(1, sh*width/height)
+			r.Min.X += (sw - cw) / 2 //nolint:mnd
+			r.Max.X = r.Min.X + cw
+		} else {
+			ch := max(1, sw*height/width)
+			r.Min.Y += (sh - ch) / 2 //nolint:mnd
+			r.Max.Y = r.Min.Y + ch
+		}
+	}
+
+	dst := stdimage.NewRGBA(stdimage.Rect(0, 0, width, height))
+	if r.Dx() == width && r.Dy() == height {
+		draw.Draw(dst, dst.Bounds(), src, r.Min, draw.Src)
+		return dst
+	}
+
+	for y := range height {
+		sy := r.Min.Y + y*r.Dy()/height
+		for x := range width {
+			sx := r.Min.X + x*r.Dx()/width
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+
+	return dst
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,153 @@
+package image
+
+import (
+	stdimage "image"
+	"image/color"
+	"strings"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestScale(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      stdimage.Rectangle
+		width    int
+		height   int
+		mode     Mode
+		expected stdimage.Rectangle
+	}{
+		{
+			name:     "contain wide",
+			src:      stdimage.Rect(0, 0, 200, 100),
+			width:    100,
+			height:   100,
+			mode:     ModeContain,
+			expected: stdimage.Rect(0, 0, 100, 50),
+		},
+		{
+			name:     "contain tall",
+			src:      stdimage.Rect(0, 0, 100, 200),
+			width:    100,
+			height:   100,
+			mode:     ModeContain,
+			expected: stdimage.Rect(0, 0, 50, 100),
+		},
+		{
+			name:     "cover",
+			src:      stdimage.Rect(0, 0, 200, 100),
+			width:    100,
+			height:   100,
+			mode:     ModeCover,
+			expected: stdimage.Rect(0, 0, 100, 100),
+		},
+		{
+			name:     "stretch",
+			src:      stdimage.Rect(0, 0, 200, 100),
+			width:    30,
+			height:   40,
+			mode:     ModeStretch,
+			expected: stdimage.Rect(0, 0, 30, 40),
+		},
+		{
+			name:     "empty target",
+			src:      stdimage.Rect(0, 0, 200, 100),
+			width:    0,
+			height:   40,
+			mode:     ModeStretch,
+			expected: stdimage.Rectangle{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			img := scale(stdimage.NewRGBA(tc.src), tc.width, tc.height, tc.mode)
+			if got := img.Bounds(); got != tc.expected {
+				t.Errorf("expected bounds %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestScaleCoverCrops(t *testing.T) {
+	// A red, green, and blue image with equal thirds. Covering a square keeps
+	// the middle third.
+	src := stdimage.NewRGBA(stdimage.Rect(0, 0, 30, 10))
+	for x := range 30 {
+		c := color.RGBA{R: 0xff, A: 0xff}
+		switch {
+		case x >= 20:
+			c = color.RGBA{B: 0xff, A: 0xff}
+		case x >= 10:
+			c = color.RGBA{G: 0xff, A: 0xff}
+		}
+		for y := range 10 {
+			src.Set(x, y, c)
+		}
+	}
+
+	img := scale(src, 5, 5, ModeCover)
+	want := color.RGBA{G: 0xff, A: 0xff}
+	for _, p := range []stdimage.Point{{0, 0}, {4, 0}, {2, 2}, {4, 4}} {
+		if got := img.At(p.X, p.Y); got != want {
+			t.Errorf("expected %v at %v, got %v", want, p, got)
+		}
+	}
+}
+
+func TestImageDraw(t *testing.T) {
+	src := stdimage.NewRGBA(stdimage.Rect(0, 0, 40, 40))
+	img := New(src)
+	img.CellSize = uv.Size{Width: 10, Height: 20}
+
+	buf := uv.NewScreenBuffer(10, 5)
+	area := uv.Rect(1, 1, 8, 3)
+	img.Draw(buf, area)
+
+	// The image is 40x40 pixels scaled to fit 80x60 pixels, which gives
+	// 60x60 pixels covering 6x3 cells.
+	cell := buf.CellAt(area.Min.X, area.Min.Y)
+	if cell == nil {
+		t.Fatal("expected a cell at the top-left of the area")
+	}
+	if !strings.HasPrefix(cell.Content, "\x1bP0;1q") {
+		t.Errorf("expected a sixel sequence, got %q", cell.Content)
+	}
+	if want := ansi.CursorPosition(8, 2); !strings.HasSuffix(cell.Content, want) {
+		t.Errorf("expected content to end with %q, got %q", want, cell.Content)
+	}
+	if cell.Width != 6 {
+		t.Errorf("expected cell width 6, got %d", cell.Width)
+	}
+	for y := area.Min.Y + 1; y < area.Max.Y; y++ {
+		cell := buf.CellAt(area.Min.X, y)
+		if cell == nil || cell.Content != ansi.CursorForward(6) || cell.Width != 6 {
+			t.Errorf("expected cursor forward cell at row %d, got %#v", y, cell)
+		}
+	}
+
+	// The encoding is cached.
+	encoded := img.encoded
+	img.Draw(buf, area.Add(stdimage.Pt(1, 1)))
+	if img.encoded != encoded {
+		t.Error("expected the encoding to be reused for the same area size")
+	}
+
+	img.Mode = ModeStretch
+	img.Draw(buf, area)
+	if img.cols != 8 || img.rows != 3 {
+		t.Errorf("expected the stretched image to cover 8x3 cells, got %dx%d", img.cols, img.rows)
+	}
+}
+
+func TestImageDrawEmpty(t *testing.T) {
+	buf := uv.NewScreenBuffer(4, 2)
+	buf.SetCell(0, 0, &uv.Cell{Content: "x", Width: 1})
+
+	New(nil).Draw(buf, buf.Bounds())
+	if cell := buf.CellAt(0, 0); cell == nil || cell.Content != " " {
+		t.Errorf("expected the area to be cleared, got %#v", cell)
+	}
+}
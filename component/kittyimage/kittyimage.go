@@ -0,0 +1,209 @@
+// Package kittyimage provides a component that draws images using the Kitty
+// graphics protocol and Unicode placeholders.
+package kittyimage
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/screen"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+)
+
+// placementID is the ID of the virtual placement used to display the image.
+// Reusing the same ID replaces the previous placement when the image is
+// placed again.
+const placementID = 1
+
+// Image is a component that draws an image using the Kitty graphics protocol.
+//
+// The image data is sent to the terminal once per image ID with
+// [Image.Transmit], which writes it straight to the terminal. The image is then
+// displayed using a virtual placement and Unicode placeholder cells that cover
+// the area it's drawn in, so the terminal scales it to the area.
+//
+// The placement sequence is prepended to the content of the top-left cell of
+// the area. It only changes with the area size, so the renderer only sends it
+// again when the image is resized, and it works on any screen, including
+// off-screen buffers that are later drawn on the terminal screen.
+type Image struct {
+	// Fallback draws the image using half block characters instead of Kitty
+	// graphics. Set it when the terminal doesn't support the Kitty graphics
+	// protocol.
+	Fallback bool
+
+	id  int
+	img image.Image
+
+	transmitted bool // whether img was written by Transmit
+}
+
+var _ uv.Drawable = (*Image)(nil)
+
+// New creates a new [Image] component with the given image ID and image. The
+// ID identifies the image in the terminal and must be unique and non-zero.
+func New(id int, img image.Image) *Image {
+	return &Image{id: id, img: img}
+}
+
+// ID returns the image ID of the component.
+func (i *Image) ID() int {
+	return i.id
+}
+
+// SetImage changes the image of the component. The new image is sent to the
+// terminal on the next call to [Image.Transmit].
+func (i *Image) SetImage(img image.Image) {
+	i.img = img
+	i.transmitted = false
+}
+
+// Transmit writes the image data to w, usually the [uv.Terminal], unless it
+// was already transmitted. It must be called before the screen the image is
+// drawn on is rendered for the image to show up.
+//
+// The image is only marked as transmitted once it's fully written to w. Use
+// [Image.Invalidate] when the terminal might have lost the image, for example
+// after a reset, to send it again on the next call.
+func (i *Image) Transmit(w io.Writer) error {
+	if i.transmitted || i.Fallback || i.img == nil || i.img.Bounds().Empty() {
+		return nil
+	}
+
+	bounds := i.img.Bounds()
+	if err := kitty.EncodeGraphics(w, i.img, &kitty.Options{
+		ID:           i.id,
+		Action:       kitty.Transmit,
+		Transmission: kitty.Direct,
+		Format:       kitty.RGBA,
+		Compression:  kitty.Zlib,
+		ImageWidth:   bounds.Dx(),
+		ImageHeight:  bounds.Dy(),
+		Chunk:        true,
+		Quite:        2, //nolint:mnd
+	}); err != nil {
+		return fmt.Errorf("failed to transmit image: %w", err)
+	}
+	i.transmitted = true
+	return nil
+}
+
+// Invalidate marks the image as not transmitted, so that the next call to
+// [Image.Transmit] sends it again.
+func (i *Image) Invalidate() {
+	i.transmitted = false
+}
+
+// Image returns the image of the component.
+func (i *Image) Image() image.Image {
+	return i.img
+}
+
+// Draw draws the image in the given area. The image is stretched to cover the
+// whole area.
+func (i *Image) Draw(scr uv.Screen, area uv.Rectangle) {
+	if area.Empty() {
+		return
+	}
+
+	screen.FillArea(scr, &uv.EmptyCell, area)
+	if i.img == nil || i.img.Bounds().Empty() {
+		return
+	}
+
+	if i.Fallback {
+		drawBlocks(scr, i.img, area)
+		return
+	}
+
+	opts := kitty.Options{
+		ID:               i.id,
+		PlacementID:      placementID,
+		Action:           kitty.Put,
+		Columns:          area.Dx(),
+		Rows:             area.Dy(),
+		VirtualPlacement: true,
+		Quite:            2, //nolint:mnd
+	}
+	seq := ansi.KittyGraphics(nil, opts.Options()...)
+
+	// The foreground color encodes the lower 24 bits of the image ID, and the
+	// third diacritic of a placeholder encodes the most significant byte.
+	fg := idColor(i.id)
+	msb := i.id >> 24 & 0xff //nolint:mnd
+
+	for y := range area.Dy() {
+		// Only the first cell of each row needs the row and column
+		// diacritics, the terminal infers them for the following cells.
+		content := []rune{kitty.Placeholder, kitty.Diacritic(y), kitty.Diacritic(0)}
+		if msb > 0 {
+			content = append(content, kitty.Diacritic(msb))
+		}
+		cell := &uv.Cell{
+			Content: string(content),
+			Width:   1,
+			Style:   uv.Style{Fg: fg},
+		}
+		if y == 0 {
+			cell.Content = seq + cell.Content
+		}
+		scr.SetCell(area.Min.X, area.Min.Y+y, cell)
+
+		for x := 1; x < area.Dx(); x++ {
+			scr.SetCell(area.Min.X+x, area.Min.Y+y, &uv.Cell{
+				Content: string(kitty.Placeholder),
+				Width:   1,
+				Style:   uv.Style{Fg: fg},
+			})
+		}
+	}
+}
+
+// idColor returns the foreground color that encodes the lower 24 bits of the
+// given image ID. IDs that fit in a single byte use an indexed color.
+func idColor(id int) color.Color {
+	r, g, b := id>>16&0xff, id>>8&0xff, id&0xff //nolint:mnd
+	if r == 0 && g == 0 {
+		return ansi.IndexedColor(b) //nolint:gosec
+	}
+	return color.RGBA{
+		R: uint8(r), //nolint:gosec
+		G: uint8(g), //nolint:gosec
+		B: uint8(b), //nolint:gosec
+		A: 0xff,
+	}
+}
+
+// drawBlocks draws the image using upper half block characters, each cell
+// holding two vertical pixels of the image.
+func drawBlocks(scr uv.Screen, img image.Image, area uv.Rectangle) {
+	bounds := img.Bounds()
+	width, height := area.Dx(), area.Dy()*2 //nolint:mnd
+	at := func(x, y int) color.Color {
+		c := img.At(
+			bounds.Min.X+x*bounds.Dx()/width,
+			bounds.Min.Y+y*bounds.Dy()/height,
+		)
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return nil
+		}
+		return c
+	}
+
+	for y := range area.Dy() {
+		for x := range width {
+			scr.SetCell(area.Min.X+x, area.Min.Y+y, &uv.Cell{
+				Content: "▀",
+				Width:   1,
+				Style: uv.Style{
+					Fg: at(x, y*2),   //nolint:mnd
+					Bg: at(x, y*2+1), //nolint:mnd
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package kittyimage
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+)
+
+func TestImageDraw(t *testing.T) {
+	img := New(42, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	buf := uv.NewScreenBuffer(10, 4)
+	area := uv.Rect(1, 1, 3, 2)
+
+	img.Draw(buf, area)
+
+	first := buf.CellAt(area.Min.X, area.Min.Y)
+	if first == nil {
+		t.Fatal("expected a cell at the top-left of the area")
+	}
+	if !strings.Contains(first.Content, "a=p") || !strings.Contains(first.Content, "U=1") {
+		t.Errorf("expected a virtual placement, got %q", first.Content)
+	}
+	if strings.Contains(first.Content, "a=t") {
+		t.Errorf("expected the image not to be transmitted by Draw, got %q", first.Content)
+	}
+
+	for y := range area.Dy() {
+		for x := range area.Dx() {
+			cell := buf.CellAt(area.Min.X+x, area.Min.Y+y)
+			want := string(kitty.Placeholder)
+			if x == 0 {
+				want += string([]rune{kitty.Diacritic(y), kitty.Diacritic(0)})
+			}
+			if cell == nil || !strings.HasSuffix(cell.Content, want) {
+				t.Errorf("expected placeholder %q at %d,%d, got %#v", want, x, y, cell)
+				continue
+			}
+			if cell.Style.Fg != ansi.IndexedColor(42) {
+				t.Errorf("expected the image ID in the foreground color at %d,%d, got %v", x, y, cell.Style.Fg)
+			}
+		}
+	}
+
+	// The placement only changes with the area size, so the renderer doesn't
+	// send it again for an image that stays in place.
+	img.Draw(buf, area)
+	if cell := buf.CellAt(area.Min.X, area.Min.Y); cell.Content != first.Content {
+		t.Errorf("expected the same placement on the second draw, got %q", cell.Content)
+	}
+
+	// A resize only emits a new placement.
+	img.Draw(buf, uv.Rect(0, 0, 4, 4))
+	cell := buf.CellAt(0, 0)
+	if !strings.Contains(cell.Content, "a=p") || !strings.Contains(cell.Content, "c=4,r=4") {
+		t.Errorf("expected a new placement after resize, got %q", cell.Content)
+	}
+	if strings.Contains(cell.Content, "a=t") || strings.Contains(cell.Content, "s=4,v=4") {
+		t.Errorf("expected the image not to be transmitted again, got %q", cell.Content)
+	}
+}
+
+func TestImageTransmit(t *testing.T) {
+	img := New(42, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+
+	var out strings.Builder
+	if err := img.Transmit(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "i=42,s=4,v=4") {
+		t.Errorf("expected the image to be transmitted, got %q", out.String())
+	}
+
+	// The image is only transmitted once per image.
+	out.Reset()
+	if err := img.Transmit(&out); err != nil || out.Len() != 0 {
+		t.Errorf("expected no output on the second transmit, got %q, %v", out.String(), err)
+	}
+
+	img.Invalidate()
+	if err := img.Transmit(&out); err != nil || !strings.Contains(out.String(), "i=42,s=4,v=4") {
+		t.Errorf("expected the image to be transmitted again after Invalidate, got %q, %v", out.String(), err)
+	}
+
+	out.Reset()
+	img.SetImage(image.NewRGBA(image.Rect(0, 0, 2, 2)))
+	if err := img.Transmit(&out); err != nil || !strings.Contains(out.String(), "s=2,v=2") {
+		t.Errorf("expected the new image to be transmitted, got %q, %v", out.String(), err)
+	}
+}
+
+func TestImageTransmitError(t *testing.T) {
+	img := New(1, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+	if err := img.Transmit(failWriter{}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// A failed write doesn't count as transmitted.
+	var out strings.Builder
+	if err := img.Transmit(&out); err != nil || out.Len() == 0 {
+		t.Errorf("expected the image to be transmitted after a failure, got %q, %v", out.String(), err)
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errors.New("closed") }
+
+func TestImageDrawTwice(t *testing.T) {
+	img := New(7, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+	area := uv.Rect(0, 0, 2, 1)
+
+	// Each buffer might be the one that reaches the terminal, so both must
+	// carry the placement.
+	for i, buf := range []uv.ScreenBuffer{uv.NewScreenBuffer(2, 1), uv.NewScreenBuffer(2, 1)} {
+		img.Draw(buf, area)
+		cell := buf.CellAt(0, 0)
+		if !strings.Contains(cell.Content, "a=p") || !strings.Contains(cell.Content, "c=2,r=1") {
+			t.Errorf("%d: expected a virtual placement, got %q", i, cell.Content)
+		}
+	}
+}
+
+func TestIDColor(t *testing.T) {
+	cases := []struct {
+		id       int
+		expected color.Color
+	}{
+		{id: 1, expected: ansi.IndexedColor(1)},
+		{id: 255, expected: ansi.IndexedColor(255)},
+		{id: 0x010203, expected: color.RGBA{R: 1, G: 2, B: 3, A: 0xff}},
+		{id: 0x04010203, expected: color.RGBA{R: 1, G: 2, B: 3, A: 0xff}},
+	}
+
+	for _, tc := range cases {
+		if got := idColor(tc.id); got != tc.expected {
+			t.Errorf("expected color %v for id %d, got %v", tc.expected, tc.id, got)
+		}
+	}
+}
+
+func TestImageDrawFallback(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.Set(0, 0, red)
+	src.Set(1, 0, red)
+	src.Set(0, 1, blue)
+	src.Set(1, 1, blue)
+
+	img := New(1, src)
+	img.Fallback = true
+
+	buf := uv.NewScreenBuffer(4, 2)
+	img.Draw(buf, uv.Rect(0, 0, 4, 1))
+	for x := range 4 {
+		cell := buf.CellAt(x, 0)
+		if cell == nil || cell.Content != "▀" || cell.Style.Fg != red || cell.Style.Bg != blue {
+			t.Errorf("expected a red over blue half block at %d, got %#v", x, cell)
+		}
+	}
+	var out strings.Builder
+	if err := img.Transmit(&out); err != nil || out.Len() != 0 {
+		t.Errorf("expected the image not to be transmitted, got %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package list provides a scrollable list component with a selected item.
+package list
+
+import (
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/screen"
+)
+
+// RenderFunc draws the item at the given index in the given one line area.
+// The selected argument reports whether the item is the selected one.
+type RenderFunc func(scr uv.Screen, area uv.Rectangle, index int, selected bool)
+
+// List is a vertical list of items with one selected item.
+//
+// The selection is moved with [List.Update], which handles the up, down,
+// page up, page down, home, and end keys. Each item takes one row, and the
+// list is scrolled to keep the selected item visible. The selected row is
+// highlighted with [List.SelectedStyle].
+type List struct {
+	// Items are the items of the list. They can contain SGR and hyperlink
+	// escape codes.
+	Items []string
+	// Style is the base style of the items and the background of the list.
+	Style uv.Style
+	// SelectedStyle is the base style of the selected row. When it's the zero
+	// value, the selected row is drawn with [List.Style] in reverse video.
+	SelectedStyle uv.Style
+	// Render, if set, is used to draw the items instead of [List.Items].
+	// [List.Len] must then be set to the number of items.
+	Render RenderFunc
+	// Len is the number of items drawn with [List.Render]. It's ignored when
+	// [List.Render] is nil.
+	Len int
+
+	selected int
+	offset   int // first visible item
+	height   int // number of visible items, from the last draw
+}
+
+var _ uv.Drawable = (*List)(nil)
+
+// New creates a new [List] with the given items.
+func New(items ...string) *List {
+	return &List{Items: items}
+}
+
+// Count returns the number of items in the list.
+func (l *List) Count() int {
+	if l.Render != nil {
+		return l.Len
+	}
+	return len(l.Items)
+}
+
+// Selected returns the index of the selected item. It's -1 when the list is
+// empty.
+func (l *List) Selected() int {
+	if l.Count() == 0 {
+		return -1
+	}
+	return max(0, min(l.selected, l.Count()-1))
+}
+
+// Select selects the item at the given index. The index is clamped to the
+// items of the list.
+func (l *List) Select(i int) {
+	l.selected = max(0, min(i, l.Count()-1))
+}
+
+// Update handles the given event and reports whether it was used by the
+// list.
+func (l *List) Update(ev uv.Event) bool {
+	k, ok := ev.(uv.KeyPressEvent)
+	if !ok {
+		return false
+	}
+
+	page := max(1, l.height)
+	switch {
+	case k.MatchString("up"):
+		l.Select(l.Selected() - 1)
+	case k.MatchString("down"):
+		l.Select(l.Selected() + 1)
+	case k.MatchString("pgup"):
+		l.Select(l.Selected() - page)
+	case k.MatchString("pgdown"):
+		l.Select(l.Selected() + page)
+	case k.MatchString("home"):
+		l.Select(0)
+	case k.MatchString("end"):
+		l.Select(l.Count() - 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// Draw draws the visible items of the list in the given area, one per row.
+func (l *List) Draw(scr uv.Screen, area uv.Rectangle) {
+	if area.Empty() {
+		return
+	}
+
+	screen.FillStyle(scr, area, l.Style)
+
+	l.height = area.Dy()
+	sel := l.Selected()
+	l.offset = max(0, min(l.offset, sel, l.Count()-l.height))
+	if sel >= l.offset+l.height {
+		l.offset = sel - l.height + 1
+	}
+
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		i := l.offset + y - area.Min.Y
+		if i >= l.Count() {
+			break
+		}
+		row := uv.Rect(area.Min.X, y, area.Dx(), 1)
+		if l.Render != nil {
+			l.Render(scr, row, i, i == sel)
+			continue
+		}
+		l.drawItem(scr, row, l.Items[i], i == sel)
+	}
+}
+
+// drawItem draws the given item in its row.
+func (l *List) drawItem(scr uv.Screen, row uv.Rectangle, item string, selected bool) {
+	style := l.Style
+	if selected {
+		if l.SelectedStyle.IsZero() {
+			style.Attrs |= uv.AttrReverse
+		} else {
+			style = l.SelectedStyle
+		}
+		screen.FillStyle(scr, row, style)
+	}
+
+	uv.NewStyledString(item).Draw(scr, row)
+
+	// Apply the row style to the cells that don't define their own.
+	screen.InheritStyle(scr, row, style)
+}
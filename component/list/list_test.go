@@ -0,0 +1,100 @@
+package list
+
+import (
+	"fmt"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestListNavigation(t *testing.T) {
+	items := make([]string, 10)
+	for i := range items {
+		items[i] = fmt.Sprint(i)
+	}
+
+	cases := []struct {
+		code     rune
+		selected int
+		expected string
+	}{
+		{uv.KeyDown, 1, "0\n1\n2"},
+		{uv.KeyDown, 2, "0\n1\n2"},
+		{uv.KeyDown, 3, "1\n2\n3"},
+		{uv.KeyPgDown, 6, "4\n5\n6"},
+		{uv.KeyUp, 5, "4\n5\n6"},
+		{uv.KeyPgUp, 2, "2\n3\n4"},
+		{uv.KeyEnd, 9, "7\n8\n9"},
+		{uv.KeyDown, 9, "7\n8\n9"},
+		{uv.KeyHome, 0, "0\n1\n2"},
+		{uv.KeyUp, 0, "0\n1\n2"},
+	}
+
+	l := New(items...)
+	scr := uv.NewScreenBuffer(3, 3)
+	l.Draw(scr, scr.Bounds())
+	for i, tc := range cases {
+		if !l.Update(uv.KeyPressEvent{Code: tc.code}) {
+			t.Fatalf("%d: expected key to be handled", i)
+		}
+		if got := l.Selected(); got != tc.selected {
+			t.Errorf("%d: expected selected %d, got %d", i, tc.selected, got)
+		}
+		l.Draw(scr, scr.Bounds())
+		if got := uv.TrimSpace(scr.String()); got != tc.expected {
+			t.Errorf("%d: expected %q, got %q", i, tc.expected, got)
+		}
+	}
+
+	if l.Update(uv.KeyPressEvent{Code: uv.KeyEnter}) {
+		t.Error("expected enter to be left to the caller")
+	}
+}
+
+func TestListHighlight(t *testing.T) {
+	l := New("a", "\x1b[31mb\x1b[m", "c")
+	l.Select(1)
+	scr := uv.NewScreenBuffer(2, 3)
+	l.Draw(scr, scr.Bounds())
+
+	for y := range 3 {
+		for x := range 2 {
+			c := scr.CellAt(x, y)
+			if reverse := c.Style.Attrs&uv.AttrReverse != 0; reverse != (y == 1) {
+				t.Errorf("expected cell (%d, %d) reverse to be %v", x, y, y == 1)
+			}
+		}
+	}
+	if c := scr.CellAt(0, 1); c.Style.Fg != ansi.Red {
+		t.Errorf("expected item style to be kept, got %#v", c.Style)
+	}
+
+	l.SelectedStyle = uv.Style{Bg: ansi.Blue}
+	l.Draw(scr, scr.Bounds())
+	if c := scr.CellAt(1, 1); c.Style.Bg != ansi.Blue {
+		t.Errorf("expected selected style, got %#v", c.Style)
+	}
+}
+
+func TestListRender(t *testing.T) {
+	var got []string
+	l := &List{
+		Len: 5,
+		Render: func(scr uv.Screen, area uv.Rectangle, index int, selected bool) {
+			got = append(got, fmt.Sprintf("%d:%v@%d", index, selected, area.Min.Y))
+		},
+	}
+	l.Select(3)
+	scr := uv.NewScreenBuffer(4, 2)
+	l.Draw(scr, scr.Bounds())
+
+	expected := []string{"2:false@0", "3:true@1"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected rows %v, got %v", expected, got)
+	}
+
+	if empty := New(); empty.Selected() != -1 {
+		t.Errorf("expected no selection in an empty list, got %d", empty.Selected())
+	}
+}
@@ -0,0 +1,110 @@
+// Package statusbar provides a status bar component with left, center, and
+// right aligned segments.
+package statusbar
+
+import (
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/screen"
+)
+
+// Segment is a single piece of text in a [StatusBar].
+type Segment struct {
+	// Text is the content of the segment. It can contain SGR and hyperlink
+	// escape codes.
+	Text string
+	// Style is the base style of the segment. Styles set by escape codes in
+	// [Segment.Text] take precedence over it.
+	Style uv.Style
+}
+
+// NewSegment creates a new [Segment] with the given text and style.
+func NewSegment(text string, style uv.Style) Segment {
+	return Segment{Text: text, Style: style}
+}
+
+// StatusBar is a single line bar split into left, center, and right groups of
+// segments.
+//
+// Left segments are drawn from the left edge of the area, right segments are
+// drawn against the right edge, and center segments are centered in the area.
+// When the area is too narrow to fit all the segments, the center group is
+// truncated first, then the right group, and finally the left group.
+type StatusBar struct {
+	// Left is the list of segments aligned to the left edge.
+	Left []Segment
+	// Center is the list of segments centered in the bar.
+	Center []Segment
+	// Right is the list of segments aligned to the right edge.
+	Right []Segment
+	// Style is used to fill the background of the bar. It's also the base
+	// style of every segment.
+	Style uv.Style
+}
+
+var _ uv.Drawable = (*StatusBar)(nil)
+
+// New creates a new empty [StatusBar].
+func New() *StatusBar {
+	return new(StatusBar)
+}
+
+// Draw draws the status bar on the first line of the given area. The rest of
+// the area is filled with the bar background.
+func (s *StatusBar) Draw(scr uv.Screen, area uv.Rectangle) {
+	if area.Empty() {
+		return
+	}
+
+	screen.FillArea(scr, &uv.Cell{Content: " ", Width: 1, Style: s.Style}, area)
+
+	m := scr.WidthMethod()
+	width := area.Dx()
+	lw := min(groupWidth(m, s.Left), width)
+	rw := min(groupWidth(m, s.Right), width-lw)
+	cw := min(groupWidth(m, s.Center), width-lw-rw)
+
+	// Center the middle group in the whole area, and push it aside when it
+	// would overlap the left or right groups.
+	cx := area.Min.X + (width-cw)/2
+	cx = max(cx, area.Min.X+lw)
+	cx = min(cx, area.Max.X-rw-cw)
+
+	y := area.Min.Y
+	s.drawGroup(scr, s.Left, area.Min.X, area.Min.X+lw, y)
+	s.drawGroup(scr, s.Center, cx, cx+cw, y)
+	s.drawGroup(scr, s.Right, area.Max.X-rw, area.Max.X, y)
+}
+
+// drawGroup draws the given segments one after the other starting at x0 and
+// clipping at x1.
+func (s *StatusBar) drawGroup(scr uv.Screen, segs []Segment, x0, x1, y int) {
+	m := scr.WidthMethod()
+	x := x0
+	for _, seg := range segs {
+		if x >= x1 {
+			break
+		}
+		w := min(m.StringWidth(seg.Text), x1-x)
+		if w <= 0 {
+			continue
+		}
+
+		area := uv.Rect(x, y, w, 1)
+		uv.NewStyledString(seg.Text).Draw(scr, area)
+
+		// Apply the segment style, and the bar style, to the cells that
+		// don't define their own.
+		screen.InheritStyle(scr, area, seg.Style)
+		screen.InheritStyle(scr, area, s.Style)
+
+		x += w
+	}
+}
+
+// groupWidth returns the total width of the given segments.
+func groupWidth(m uv.WidthMethod, segs []Segment) (w int) {
+	for _, seg := range segs {
+		w += m.StringWidth(seg.Text)
+	}
+	return
+}
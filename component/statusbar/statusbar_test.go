@@ -0,0 +1,134 @@
+package statusbar
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestStatusBar(t *testing.T) {
+	cases := []struct {
+		name     string
+		width    int
+		left     []string
+		center   []string
+		right    []string
+		expected string
+	}{
+		{
+			name:     "all fit",
+			width:    20,
+			left:     []string{"NOR", " "},
+			center:   []string{"main.go"},
+			right:    []string{"1:1"},
+			expected: "NOR   main.go    1:1",
+		},
+		{
+			name:     "center pushed by left",
+			width:    12,
+			left:     []string{"NORMAL"},
+			center:   []string{"abc"},
+			right:    []string{"1"},
+			expected: "NORMALabc  1",
+		},
+		{
+			name:     "center truncated first",
+			width:    10,
+			left:     []string{"NOR"},
+			center:   []string{"main.go"},
+			right:    []string{"1:1"},
+			expected: "NORmain1:1",
+		},
+		{
+			name:     "center dropped",
+			width:    6,
+			left:     []string{"NOR"},
+			center:   []string{"main.go"},
+			right:    []string{"1:1"},
+			expected: "NOR1:1",
+		},
+		{
+			name:     "right truncated after center",
+			width:    5,
+			left:     []string{"NOR"},
+			center:   []string{"main.go"},
+			right:    []string{"1:1"},
+			expected: "NOR1:",
+		},
+		{
+			name:     "left truncated last",
+			width:    2,
+			left:     []string{"NOR"},
+			right:    []string{"1:1"},
+			expected: "NO",
+		},
+		{
+			name:     "multiple segments truncated",
+			width:    7,
+			left:     []string{"ab", "cd", "ef", "gh"},
+			expected: "abcdefg",
+		},
+		{
+			name:     "wide runes",
+			width:    9,
+			left:     []string{"你好"},
+			center:   []string{"世界"},
+			right:    []string{"ab"},
+			expected: "你好世 ab",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sb := New()
+			sb.Left = segments(tc.left)
+			sb.Center = segments(tc.center)
+			sb.Right = segments(tc.right)
+			sb.Style = uv.Style{Bg: ansi.Blue}
+
+			buf := uv.NewScreenBuffer(tc.width, 1)
+			sb.Draw(buf, buf.Bounds())
+
+			if got := buf.Lines[0].String(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+			for x, c := range buf.Lines[0] {
+				if c.Width > 0 && c.Style.Bg != ansi.Blue {
+					t.Errorf("expected cell %d to have the bar background, got %v", x, c.Style.Bg)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusBarSegmentStyle(t *testing.T) {
+	sb := New()
+	sb.Style = uv.Style{Bg: ansi.Blue}
+	sb.Left = []Segment{
+		NewSegment("a", uv.Style{Fg: ansi.Red}),
+		NewSegment("\x1b[32mb", uv.Style{Fg: ansi.Red, Bg: ansi.Black}),
+	}
+
+	buf := uv.NewScreenBuffer(3, 1)
+	sb.Draw(buf, buf.Bounds())
+
+	expected := []uv.Style{
+		{Fg: ansi.Red, Bg: ansi.Blue},
+		{Fg: ansi.Green, Bg: ansi.Black},
+		{Bg: ansi.Blue},
+	}
+	for x, style := range expected {
+		if c := buf.CellAt(x, 0); !c.Style.Equal(&style) {
+			t.Errorf("expected cell %d style %#v, got %#v", x, style, c.Style)
+		}
+	}
+}
+
+func segments(texts []string) []Segment {
+	segs := make([]Segment, len(texts))
+	for i, text := range texts {
+		segs[i] = Segment{Text: text}
+	}
+	return segs
+}
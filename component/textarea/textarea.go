@@ -0,0 +1,360 @@
+// Package textarea provides a multi-line text area component with soft
+// wrapping.
+package textarea
+
+import (
+	"strings"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/internal/text"
+	"github.com/charmbracelet/ultraviolet/screen"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TextArea is a multi-line text editing field.
+//
+// Lines longer than the field are soft wrapped at word boundaries, or at the
+// field edge for words that don't fit on a line of their own. The value is
+// edited with [TextArea.Update], which handles printable keys, pastes, enter,
+// backspace and delete, and moving the cursor. Up and down move the cursor
+// across wrapped lines as they are displayed, keeping its column. The view is
+// scrolled vertically to keep the cursor visible, and the cursor is drawn as a
+// reverse video cell at the edit position.
+//
+// Wrapping depends on the size and width method of the last
+// [TextArea.Draw] call. Before the first draw, lines are not wrapped.
+type TextArea struct {
+	// Style is the style of the text and the background of the field.
+	Style uv.Style
+	// CursorStyle is the style of the cursor cell. When it's the zero value,
+	// the cursor is drawn with [TextArea.Style] in reverse video.
+	CursorStyle uv.Style
+
+	// The value is kept as logical lines of grapheme clusters, so moving one
+	// element left or right is already grapheme-aware. The cell helpers of
+	// [uv.Buffer] don't apply here since they walk the drawn rows, which only
+	// cover the visible, wrapped part of the value.
+	lines    [][]string     // grapheme clusters of each line
+	row, col int            // cursor position, col is in graphemes
+	goal     int            // column to keep when moving up and down, -1 if unset
+	offset   int            // first visible row
+	width    int            // wrap width
+	method   uv.WidthMethod // width method used to wrap
+}
+
+var _ uv.Drawable = (*TextArea)(nil)
+
+// New creates a new empty [TextArea].
+func New() *TextArea {
+	t := new(TextArea)
+	t.SetValue("")
+	return t
+}
+
+// Value returns the current value of the text area. Lines are separated by
+// newlines.
+func (t *TextArea) Value() string {
+	lines := make([]string, len(t.lines))
+	for i, l := range t.lines {
+		lines[i] = strings.Join(l, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetValue sets the value of the text area and moves the cursor to its end.
+func (t *TextArea) SetValue(s string) {
+	t.lines = t.lines[:0]
+	for l := range strings.SplitSeq(normalize(s), "\n") {
+		t.lines = append(t.lines, text.Graphemes(l))
+	}
+	t.row = len(t.lines) - 1
+	t.col = len(t.lines[t.row])
+	t.goal = -1
+}
+
+// Cursor returns the cursor line and column. The column is in grapheme
+// clusters from the start of the line.
+func (t *TextArea) Cursor() (line, col int) {
+	return t.row, t.col
+}
+
+// SetCursor moves the cursor to the given line and column, in grapheme
+// clusters from the start of the line. The position is clamped to the
+// value.
+func (t *TextArea) SetCursor(line, col int) {
+	t.row = max(0, min(line, len(t.lines)-1))
+	t.col = max(0, min(col, len(t.lines[t.row])))
+	t.goal = -1
+}
+
+// Update handles the given event and reports whether it was used by the text
+// area.
+func (t *TextArea) Update(ev uv.Event) bool {
+	switch ev := ev.(type) {
+	case uv.PasteEvent:
+		t.insert(ev.Content)
+	case uv.KeyPressEvent:
+		if !t.handleKey(ev) {
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (t *TextArea) handleKey(ev uv.KeyPressEvent) bool {
+	switch {
+	case ev.MatchString("up"):
+		t.moveRow(-1)
+		return true
+	case ev.MatchString("down"):
+		t.moveRow(1)
+		return true
+	}
+
+	t.goal = -1
+	line := t.lines[t.row]
+	switch {
+	case ev.MatchString("enter"):
+		t.insert("\n")
+	case ev.MatchString("backspace"):
+		switch {
+		case t.col > 0:
+			t.lines[t.row] = append(line[:t.col-1], line[t.col:]...)
+			t.col--
+		case t.row > 0:
+			t.row--
+			t.col = len(t.lines[t.row])
+			t.joinLine()
+		}
+	case ev.MatchString("delete"):
+		if t.col < len(line) {
+			t.lines[t.row] = append(line[:t.col], line[t.col+1:]...)
+		} else if t.row < len(t.lines)-1 {
+			t.joinLine()
+		}
+	case ev.MatchString("left"):
+		switch {
+		case t.col > 0:
+			t.col--
+		case t.row > 0:
+			t.row--
+			t.col = len(t.lines[t.row])
+		}
+	case ev.MatchString("right"):
+		switch {
+		case t.col < len(line):
+			t.col++
+		case t.row < len(t.lines)-1:
+			t.row++
+			t.col = 0
+		}
+	case ev.MatchString("home", "ctrl+a"):
+		t.col = t.rows()[t.cursorRow()].start
+	case ev.MatchString("end", "ctrl+e"):
+		r := t.rows()[t.cursorRow()]
+		t.col = r.end
+		if r.end < len(line) && r.end > r.start {
+			// Stay on the wrapped row instead of moving to the next one.
+			t.col--
+		}
+	case ev.Text != "" && ev.Mod&^uv.ModShift == 0:
+		t.insert(ev.Text)
+	default:
+		return false
+	}
+	return true
+}
+
+// Draw draws the text area in the given area.
+func (t *TextArea) Draw(scr uv.Screen, area uv.Rectangle) {
+	if area.Empty() {
+		return
+	}
+
+	screen.FillStyle(scr, area, t.Style)
+
+	t.width, t.method = area.Dx(), scr.WidthMethod()
+	rows := t.rows()
+	cur := t.cursorRow()
+	t.offset = max(0, min(t.offset, cur, len(rows)-area.Dy()))
+	if cur >= t.offset+area.Dy() {
+		t.offset = cur - area.Dy() + 1
+	}
+
+	cursor := t.CursorStyle
+	if cursor.IsZero() {
+		cursor = t.Style
+		cursor.Attrs |= uv.AttrReverse
+	}
+
+	for y := area.Min.Y; y < area.Max.Y && t.offset+y-area.Min.Y < len(rows); y++ {
+		r := rows[t.offset+y-area.Min.Y]
+		line := t.lines[r.line]
+		x := area.Min.X
+		for i := r.start; i <= r.end; i++ {
+			cell := uv.Cell{Content: " ", Width: 1, Style: t.Style}
+			if i < r.end {
+				cell.Content, cell.Width = line[i], t.method.StringWidth(line[i])
+			} else if r.line != t.row || i != t.col || i != len(line) {
+				// The cursor cell past the end of the line.
+				break
+			}
+			if x+cell.Width > area.Max.X {
+				break
+			}
+			if r.line == t.row && i == t.col {
+				cell.Style = cursor
+			}
+			scr.SetCell(x, y, &cell)
+			x += cell.Width
+		}
+	}
+}
+
+// row is a wrapped line as displayed, with the grapheme range it shows.
+type row struct {
+	line       int
+	start, end int
+}
+
+// rows returns the wrapped rows of all the lines.
+func (t *TextArea) rows() (rows []row) {
+	for i, l := range t.lines {
+		for _, r := range t.wrap(l) {
+			rows = append(rows, row{line: i, start: r[0], end: r[1]})
+		}
+	}
+	return
+}
+
+// wrap returns the grapheme ranges of the rows of the given line. When the
+// last row is full, an empty row is added to hold the cursor at the end of
+// the line.
+func (t *TextArea) wrap(line []string) (rows [][2]int) {
+	if t.width <= 0 || t.method == nil {
+		return [][2]int{{0, len(line)}}
+	}
+
+	w, start := 0, 0
+	for start < len(line) {
+		end, brk := start, -1
+		for w = 0; end < len(line); end++ {
+			gw := t.method.StringWidth(line[end])
+			if w+gw > t.width {
+				break
+			}
+			w += gw
+			if line[end] == " " {
+				brk = end + 1
+			}
+		}
+		switch {
+		case end < len(line) && line[end] == " ":
+			// Let the space hang past the edge instead of starting the
+			// next row.
+			end++
+		case end < len(line) && brk > start:
+			// Break after the last space.
+			end = brk
+		case end == start:
+			// A grapheme wider than the field.
+			end++
+		}
+		rows = append(rows, [2]int{start, end})
+		start = end
+	}
+	if len(rows) == 0 || t.textWidth(line[rows[len(rows)-1][0]:]) >= t.width {
+		rows = append(rows, [2]int{len(line), len(line)})
+	}
+	return rows
+}
+
+// cursorRow returns the index of the row the cursor is on.
+func (t *TextArea) cursorRow() int {
+	rows := t.rows()
+	for i, r := range rows {
+		if r.line != t.row {
+			continue
+		}
+		last := i == len(rows)-1 || rows[i+1].line != t.row
+		if t.col >= r.start && (t.col < r.end || last) {
+			return i
+		}
+	}
+	return 0
+}
+
+// moveRow moves the cursor n rows up or down, keeping its column.
+func (t *TextArea) moveRow(n int) {
+	rows := t.rows()
+	cur := t.cursorRow()
+	if t.goal < 0 {
+		r := rows[cur]
+		t.goal = t.textWidth(t.lines[r.line][r.start:t.col])
+	}
+
+	next := cur + n
+	if next < 0 || next >= len(rows) {
+		return
+	}
+
+	r := rows[next]
+	line := t.lines[r.line]
+	last := next == len(rows)-1 || rows[next+1].line != r.line
+	col, x := r.start, 0
+	for col < r.end {
+		gw := t.width1(line[col])
+		if x+gw > t.goal || (col == r.end-1 && !last) {
+			break
+		}
+		x += gw
+		col++
+	}
+	t.row, t.col = r.line, col
+}
+
+// joinLine joins the cursor line with the next one.
+func (t *TextArea) joinLine() {
+	t.lines[t.row] = append(t.lines[t.row], t.lines[t.row+1]...)
+	t.lines = append(t.lines[:t.row+1], t.lines[t.row+2:]...)
+}
+
+// insert inserts s at the cursor position and moves the cursor after it.
+func (t *TextArea) insert(s string) {
+	t.goal = -1
+	line := t.lines[t.row]
+	tail := append([]string(nil), line[t.col:]...)
+	t.lines[t.row] = line[:t.col]
+
+	parts := strings.Split(normalize(s), "\n")
+	for i, p := range parts {
+		if i > 0 {
+			t.row++
+			t.lines = append(t.lines[:t.row], append([][]string{nil}, t.lines[t.row:]...)...)
+		}
+		t.lines[t.row] = append(t.lines[t.row], text.Graphemes(p)...)
+	}
+	t.col = len(t.lines[t.row])
+	t.lines[t.row] = append(t.lines[t.row], tail...)
+}
+
+func (t *TextArea) textWidth(grs []string) (w int) {
+	for _, gr := range grs {
+		w += t.width1(gr)
+	}
+	return
+}
+
+func (t *TextArea) width1(gr string) int {
+	if t.method == nil {
+		return ansi.StringWidth(gr)
+	}
+	return t.method.StringWidth(gr)
+}
+
+// normalize converts line endings to newlines.
+func normalize(s string) string {
+	return strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(s)
+}
@@ -0,0 +1,171 @@
+package textarea
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+func key(s string) uv.Event {
+	switch s {
+	case "enter":
+		return uv.KeyPressEvent{Code: uv.KeyEnter}
+	case "backspace":
+		return uv.KeyPressEvent{Code: uv.KeyBackspace}
+	case "delete":
+		return uv.KeyPressEvent{Code: uv.KeyDelete}
+	case "up":
+		return uv.KeyPressEvent{Code: uv.KeyUp}
+	case "down":
+		return uv.KeyPressEvent{Code: uv.KeyDown}
+	case "left":
+		return uv.KeyPressEvent{Code: uv.KeyLeft}
+	case "right":
+		return uv.KeyPressEvent{Code: uv.KeyRight}
+	case "home":
+		return uv.KeyPressEvent{Code: uv.KeyHome}
+	case "end":
+		return uv.KeyPressEvent{Code: uv.KeyEnd}
+	}
+	return uv.KeyPressEvent{Code: []rune(s)[0], Text: s}
+}
+
+func draw(t *TextArea, w, h int) string {
+	scr := uv.NewScreenBuffer(w, h)
+	t.Draw(scr, scr.Bounds())
+	return uv.TrimSpace(scr.String())
+}
+
+func TestTextAreaEditing(t *testing.T) {
+	cases := []struct {
+		name     string
+		events   []uv.Event
+		expected string
+		line     int
+		col      int
+	}{
+		{
+			name:     "enter",
+			events:   []uv.Event{key("a"), key("b"), key("left"), key("enter")},
+			expected: "a\nb",
+			line:     1,
+		},
+		{
+			name:     "backspace joins lines",
+			events:   []uv.Event{key("a"), key("enter"), key("b"), key("home"), key("backspace")},
+			expected: "ab",
+			col:      1,
+		},
+		{
+			name:     "delete joins lines",
+			events:   []uv.Event{key("a"), key("enter"), key("b"), key("up"), key("end"), key("delete")},
+			expected: "ab",
+			col:      1,
+		},
+		{
+			name:     "left and right cross lines",
+			events:   []uv.Event{key("a"), key("enter"), key("home"), key("left"), key("x"), key("right"), key("right"), key("y")},
+			expected: "ax\ny",
+			line:     1,
+			col:      1,
+		},
+		{
+			name:     "paste",
+			events:   []uv.Event{key("a"), key("left"), uv.PasteEvent{Content: "1\r\n2"}},
+			expected: "1\n2a",
+			line:     1,
+			col:      1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ta := New()
+			for _, ev := range tc.events {
+				ta.Update(ev)
+			}
+			if got := ta.Value(); got != tc.expected {
+				t.Errorf("expected value %q, got %q", tc.expected, got)
+			}
+			if line, col := ta.Cursor(); line != tc.line || col != tc.col {
+				t.Errorf("expected cursor at %d:%d, got %d:%d", tc.line, tc.col, line, col)
+			}
+		})
+	}
+}
+
+func TestTextAreaWrap(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "words", value: "the quick brown fox", expected: "the\nquick\nbrown\nfox\n"},
+		{name: "long word", value: "abcdefghij", expected: "abcde\nfghij\n\n\n"},
+		{name: "newlines", value: "ab\n\ncd", expected: "ab\n\ncd\n\n"},
+		{name: "wide", value: "你好你好", expected: "你好\n你好\n\n\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ta := New()
+			ta.SetValue(tc.value)
+			if got := draw(ta, 5, 5); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTextAreaUpDown(t *testing.T) {
+	ta := New()
+	ta.SetValue("abcd efgh ij\nxy")
+	ta.SetCursor(0, 7) // the "g" on the second row
+	draw(ta, 6, 4)
+
+	// "abcd " / "efgh " / "ij" / "xy"
+	cases := []struct {
+		key  string
+		line int
+		col  int
+	}{
+		{"up", 0, 2},
+		{"up", 0, 2},
+		{"down", 0, 7},
+		{"down", 0, 12},
+		{"down", 1, 2},
+		{"up", 0, 12},
+		{"up", 0, 7},
+	}
+	for i, tc := range cases {
+		ta.Update(key(tc.key))
+		if line, col := ta.Cursor(); line != tc.line || col != tc.col {
+			t.Errorf("%d: expected cursor at %d:%d after %s, got %d:%d", i, tc.line, tc.col, tc.key, line, col)
+		}
+	}
+
+	// The column is kept at the end of wrapped rows.
+	ta.SetCursor(0, 4)
+	ta.Update(key("down"))
+	if line, col := ta.Cursor(); line != 0 || col != 9 {
+		t.Errorf("expected cursor at 0:9, got %d:%d", line, col)
+	}
+}
+
+func TestTextAreaScroll(t *testing.T) {
+	ta := New()
+	ta.SetValue("a\nb\nc\nd")
+	if got, expected := draw(ta, 3, 2), "c\nd"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	ta.SetCursor(0, 0)
+	if got, expected := draw(ta, 3, 2), "a\nb"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	scr := uv.NewScreenBuffer(3, 2)
+	ta.Draw(scr, scr.Bounds())
+	if c := scr.CellAt(0, 0); c.Style.Attrs&uv.AttrReverse == 0 {
+		t.Errorf("expected cursor at the start, got %#v", c)
+	}
+}
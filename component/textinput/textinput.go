@@ -0,0 +1,163 @@
+// Package textinput provides a single-line text input component.
+package textinput
+
+import (
+	"strings"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/ultraviolet/internal/text"
+	"github.com/charmbracelet/ultraviolet/screen"
+)
+
+// TextInput is a single-line text input field.
+//
+// The value is edited with [TextInput.Update], which handles printable keys,
+// pastes, backspace and delete, and moving the cursor with the left, right,
+// home, and end keys. When the value doesn't fit in the field, it's scrolled
+// horizontally to keep the cursor visible. The cursor is drawn as a reverse
+// video cell at the edit position.
+type TextInput struct {
+	// Style is the style of the text and the background of the field.
+	Style uv.Style
+	// CursorStyle is the style of the cursor cell. When it's the zero value,
+	// the cursor is drawn with [TextInput.Style] in reverse video.
+	CursorStyle uv.Style
+
+	value  []string // grapheme clusters
+	pos    int      // cursor position, in graphemes
+	offset int      // first visible grapheme
+}
+
+var _ uv.Drawable = (*TextInput)(nil)
+
+// New creates a new empty [TextInput].
+func New() *TextInput {
+	return new(TextInput)
+}
+
+// Value returns the current value of the input.
+func (t *TextInput) Value() string {
+	return strings.Join(t.value, "")
+}
+
+// SetValue sets the value of the input and moves the cursor to its end.
+func (t *TextInput) SetValue(s string) {
+	t.value = text.Graphemes(s)
+	t.pos = len(t.value)
+}
+
+// Cursor returns the cursor position, in grapheme clusters from the start of
+// the value.
+func (t *TextInput) Cursor() int {
+	return t.pos
+}
+
+// SetCursor moves the cursor to the given position, in grapheme clusters
+// from the start of the value. The position is clamped to the value.
+func (t *TextInput) SetCursor(pos int) {
+	t.pos = max(0, min(pos, len(t.value)))
+}
+
+// Update handles the given event and reports whether it was used by the
+// input. Unhandled events, like the enter key, are left to the caller.
+func (t *TextInput) Update(ev uv.Event) bool {
+	switch ev := ev.(type) {
+	case uv.PasteEvent:
+		t.insert(strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(ev.Content))
+		return true
+	case uv.KeyPressEvent:
+		switch {
+		case ev.MatchString("backspace"):
+			if t.pos > 0 {
+				t.value = append(t.value[:t.pos-1], t.value[t.pos:]...)
+				t.pos--
+			}
+		case ev.MatchString("delete"):
+			if t.pos < len(t.value) {
+				t.value = append(t.value[:t.pos], t.value[t.pos+1:]...)
+			}
+		case ev.MatchString("left"):
+			t.SetCursor(t.pos - 1)
+		case ev.MatchString("right"):
+			t.SetCursor(t.pos + 1)
+		case ev.MatchString("home", "ctrl+a"):
+			t.pos = 0
+		case ev.MatchString("end", "ctrl+e"):
+			t.pos = len(t.value)
+		case ev.Text != "" && ev.Mod&^uv.ModShift == 0:
+			t.insert(ev.Text)
+		default:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// Draw draws the input on the first line of the given area. The rest of the
+// area is filled with the input background.
+func (t *TextInput) Draw(scr uv.Screen, area uv.Rectangle) {
+	if area.Empty() {
+		return
+	}
+
+	screen.FillStyle(scr, area, t.Style)
+
+	m := scr.WidthMethod()
+	t.scroll(m, area.Dx())
+
+	cursor := t.CursorStyle
+	if cursor.IsZero() {
+		cursor = t.Style
+		cursor.Attrs |= uv.AttrReverse
+	}
+
+	x, y := area.Min.X, area.Min.Y
+	for i := t.offset; i <= len(t.value); i++ {
+		cell := uv.Cell{Content: " ", Width: 1, Style: t.Style}
+		if i < len(t.value) {
+			cell.Content, cell.Width = t.value[i], m.StringWidth(t.value[i])
+		}
+		if x+cell.Width > area.Max.X {
+			break
+		}
+		if i == t.pos {
+			cell.Style = cursor
+		}
+		scr.SetCell(x, y, &cell)
+		x += cell.Width
+	}
+}
+
+// scroll updates the scroll offset so that the cursor is visible in a field
+// of the given width.
+func (t *TextInput) scroll(m uv.WidthMethod, width int) {
+	t.pos = max(0, min(t.pos, len(t.value)))
+	t.offset = max(0, min(t.offset, t.pos))
+	for t.offset < t.pos && t.width(m, t.offset, t.pos+1) > width {
+		t.offset++
+	}
+}
+
+// width returns the width of the graphemes from start to end, where the
+// position past the end of the value is the cursor cell.
+func (t *TextInput) width(m uv.WidthMethod, start, end int) (w int) {
+	for i := start; i < end; i++ {
+		if i < len(t.value) {
+			w += m.StringWidth(t.value[i])
+		} else {
+			w++
+		}
+	}
+	return
+}
+
+// insert inserts s at the cursor position and moves the cursor after it.
+func (t *TextInput) insert(s string) {
+	grs := text.Graphemes(s)
+	if len(grs) == 0 {
+		return
+	}
+	t.value = append(t.value[:t.pos], append(grs, t.value[t.pos:]...)...)
+	t.pos += len(grs)
+}
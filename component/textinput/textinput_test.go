@@ -0,0 +1,133 @@
+package textinput
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+func key(s string) uv.Event {
+	switch s {
+	case "backspace":
+		return uv.KeyPressEvent{Code: uv.KeyBackspace}
+	case "delete":
+		return uv.KeyPressEvent{Code: uv.KeyDelete}
+	case "left":
+		return uv.KeyPressEvent{Code: uv.KeyLeft}
+	case "right":
+		return uv.KeyPressEvent{Code: uv.KeyRight}
+	case "home":
+		return uv.KeyPressEvent{Code: uv.KeyHome}
+	case "end":
+		return uv.KeyPressEvent{Code: uv.KeyEnd}
+	}
+	r := []rune(s)[0]
+	return uv.KeyPressEvent{Code: r, Text: s}
+}
+
+func TestTextInputUpdate(t *testing.T) {
+	cases := []struct {
+		name     string
+		events   []uv.Event
+		expected string
+		cursor   int
+	}{
+		{
+			name:     "insert",
+			events:   []uv.Event{key("a"), key("b"), key("c")},
+			expected: "abc",
+			cursor:   3,
+		},
+		{
+			name:     "insert in the middle",
+			events:   []uv.Event{key("a"), key("c"), key("left"), key("b")},
+			expected: "abc",
+			cursor:   2,
+		},
+		{
+			name:     "backspace",
+			events:   []uv.Event{key("a"), key("b"), key("backspace"), key("home"), key("backspace")},
+			expected: "a",
+			cursor:   0,
+		},
+		{
+			name:     "delete",
+			events:   []uv.Event{key("a"), key("b"), key("home"), key("delete"), key("end"), key("delete")},
+			expected: "b",
+			cursor:   1,
+		},
+		{
+			name:     "cursor is clamped",
+			events:   []uv.Event{key("a"), key("right"), key("home"), key("left")},
+			expected: "a",
+			cursor:   0,
+		},
+		{
+			name:     "graphemes",
+			events:   []uv.Event{key("你"), uv.PasteEvent{Content: "é\nx"}, key("left"), key("backspace")},
+			expected: "你éx",
+			cursor:   2,
+		},
+		{
+			name:     "modifiers are ignored",
+			events:   []uv.Event{uv.KeyPressEvent{Code: 'a', Text: "a", Mod: uv.ModAlt}, key("b")},
+			expected: "b",
+			cursor:   1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ti := New()
+			for _, ev := range tc.events {
+				ti.Update(ev)
+			}
+			if got := ti.Value(); got != tc.expected {
+				t.Errorf("expected value %q, got %q", tc.expected, got)
+			}
+			if got := ti.Cursor(); got != tc.cursor {
+				t.Errorf("expected cursor %d, got %d", tc.cursor, got)
+			}
+		})
+	}
+
+	ti := New()
+	if ti.Update(uv.KeyPressEvent{Code: uv.KeyEnter}) {
+		t.Error("expected enter to be left to the caller")
+	}
+}
+
+func TestTextInputDraw(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		cursor   int
+		expected string
+		cursorX  int
+	}{
+		{name: "fits", value: "ab", cursor: 2, expected: "ab", cursorX: 2},
+		{name: "scrolled to cursor", value: "abcdef", cursor: 6, expected: "def", cursorX: 3},
+		{name: "cursor in the middle", value: "abcdef", cursor: 1, expected: "abcd", cursorX: 1},
+		{name: "wide", value: "a你b", cursor: 2, expected: "a你b", cursorX: 3},
+		{name: "wide scrolled", value: "a你b", cursor: 3, expected: "你b", cursorX: 3},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ti := New()
+			ti.SetValue(tc.value)
+			ti.SetCursor(tc.cursor)
+			scr := uv.NewScreenBuffer(4, 1)
+			ti.Draw(scr, scr.Bounds())
+			if got := uv.TrimSpace(scr.String()); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+			for x := range 4 {
+				c := scr.CellAt(x, 0)
+				if reverse := c.Style.Attrs&uv.AttrReverse != 0; reverse != (x == tc.cursorX) {
+					t.Errorf("expected cursor at %d, got reverse %v at %d", tc.cursorX, reverse, x)
+				}
+			}
+		})
+	}
+}
@@ -298,6 +298,33 @@ func (p *EventDecoder) Decode(buf []byte) (n int, Event Event) {
 	}
 }
 
+// DecodeAll decodes all the events in the given buffer. Events in a
+// [MultiEvent] are flattened into the result. Decoding stops at the end of the
+// buffer or when no more sequences can be recognized.
+//
+// Unlike [TerminalReader], DecodeAll doesn't wait for more input, incomplete
+// sequences at the end of the buffer are decoded as is. This is useful to
+// decode captured input, for example, in tests or when replaying a session.
+func (p *EventDecoder) DecodeAll(buf []byte) []Event {
+	var events []Event
+	for len(buf) > 0 {
+		n, ev := p.Decode(buf)
+		if n == 0 {
+			break
+		}
+		buf = buf[n:]
+
+		switch ev := ev.(type) {
+		case nil, ignoredEvent:
+		case MultiEvent:
+			events = append(events, ev...)
+		default:
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
 func (p *EventDecoder) parseCsi(b []byte) (int, Event) {
 	if len(b) == 2 && b[0] == ansi.ESC {
 		// short cut if this is an alt+[ key
@@ -1105,6 +1132,13 @@ func (p *EventDecoder) parseUtf8(b []byte) (int, Event) {
 		return 1, k
 	}
 
+	if !utf8.FullRune(b) {
+		// The rest of the rune hasn't been read yet. Report the incomplete
+		// rune as a whole so that it can be decoded again once more input
+		// is received.
+		return len(b), UnknownEvent(b)
+	}
+
 	code, _ := utf8.DecodeRune(b)
 	if code == utf8.RuneError {
 		return 1, UnknownEvent(b[0])
@@ -1413,7 +1447,7 @@ func parseKittyKeyboard(params ansi.Params) (Event Event) {
 				}
 
 			case 2:
-				// shifted key + base key
+				// base key
 				if b := rune(p.Param(1)); unicode.IsPrint(b) {
 					// XXX: When alternate key reporting is enabled, the protocol
 					// can return 3 things, the unicode codepoint of the key,
@@ -1423,7 +1457,6 @@ func parseKittyKeyboard(params ansi.Params) (Event Event) {
 					// when using a different language layout.
 					key.BaseCode = b
 				}
-				fallthrough
 
 			case 1:
 				// shifted key
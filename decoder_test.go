@@ -2,6 +2,7 @@ package uv
 
 import (
 	"image/color"
+	"reflect"
 	"testing"
 
 	"github.com/charmbracelet/x/ansi"
@@ -443,6 +444,49 @@ func TestParseUtf8(t *testing.T) {
 	}
 }
 
+// TestDecodeAll tests decoding all the events in a buffer
+func TestDecodeAll(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Event
+	}{
+		{
+			name:  "empty input",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "keys and text",
+			input: "a\x1b[A\x00",
+			want: []Event{
+				KeyPressEvent{Code: 'a', Text: "a"},
+				KeyPressEvent{Code: KeyUp},
+				KeyPressEvent{Code: KeySpace, Mod: ModCtrl},
+			},
+		},
+		{
+			name:  "multi event flattened",
+			input: "\x1b[1;3Rb",
+			want: []Event{
+				KeyPressEvent{Code: KeyF3, Mod: ModAlt},
+				CursorPositionEvent{Y: 0, X: 2},
+				KeyPressEvent{Code: 'b', Text: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p EventDecoder
+			got := p.DecodeAll([]byte(tt.input))
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Errorf("expected events %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
 // TestParseControl tests control character parsing
 func TestParseControl(t *testing.T) {
 	var p EventDecoder
@@ -43,4 +43,11 @@
 //
 //   - screen — drawing context and screen manipulation helpers
 //   - layout — constraint-based layout solver (Cassowary algorithm)
+//   - component/border — border with a title around another component
+//   - component/statusbar — status bar with left, center, and right segments
+//   - component/textinput — single-line text input with cursor and scrolling
+//   - component/textarea — multi-line text area with soft wrapping
+//   - component/list — scrollable list with a selected item
+//   - component/image — Sixel image scaled to fit an area
+//   - component/kittyimage — Kitty graphics image using Unicode placeholders
 package uv
@@ -318,6 +318,11 @@ type FocusEvent struct{}
 // This occurs when the terminal loses focus.
 type BlurEvent struct{}
 
+// ResumeEvent is sent when the program resumes after being suspended with
+// [Terminal.Suspend]. The terminal size might have changed while the program
+// was suspended, a new [WindowSizeEvent] is sent along with it.
+type ResumeEvent struct{}
+
 // DarkColorSchemeEvent is sent when the operating system is using a dark color
 // scheme. This is typically used to notify applications of the current or new
 // system color scheme.
@@ -333,6 +338,9 @@ type LightColorSchemeEvent struct{}
 type PasteEvent struct {
 	// Content is the pasted text content.
 	Content string
+	// Truncated reports whether the pasted text was larger than the maximum
+	// paste size and got cut off. See [TerminalReader.SetMaxPasteSize].
+	Truncated bool
 }
 
 // String returns the pasted content as a string.
@@ -400,7 +400,7 @@ func TestEventTypes(t *testing.T) {
 	_ = BlurEvent{}
 	_ = DarkColorSchemeEvent{}
 	_ = LightColorSchemeEvent{}
-	_ = PasteEvent{"pasted text"}
+	_ = PasteEvent{Content: "pasted text"}
 	_ = PasteStartEvent{}
 	_ = PasteEndEvent{}
 	_ = TerminalVersionEvent{"1.0.0"}
@@ -6,7 +6,6 @@ import (
 	"log"
 	"math/rand"
 	"os"
-	"slices"
 	"sync"
 
 	uv "github.com/charmbracelet/ultraviolet"
@@ -22,7 +21,6 @@ type AppWindow struct {
 	id  string
 	win *uv.Window
 	ctx *screen.Context
-	z   int
 	st  uv.Style
 }
 
@@ -50,7 +48,6 @@ type App struct {
 	scr         *uv.Window
 	root        *AppWindow
 	wins        map[string]*AppWindow
-	zwins       []*AppWindow
 	active      string
 	mtx         sync.RWMutex
 	quit        bool
@@ -72,13 +69,11 @@ func NewApp(width, height int) *App {
 		id:  rootID,
 		win: a.scr.NewWindow(0, 0, width, height),
 		ctx: screen.NewContext(a.scr),
-		z:   0,
 	}
 	a.root = root
 	a.wins = map[string]*AppWindow{
 		root.id: root,
 	}
-	a.zwins = []*AppWindow{root}
 	return a
 }
 
@@ -87,21 +82,9 @@ func (a *App) BringToFront(id string) {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
-	awin, ok := a.wins[id]
-	if !ok {
-		return
-	}
-
-	// Remove the window from its current position.
-	for i, zw := range a.zwins {
-		if zw.id == id {
-			a.zwins = append(a.zwins[:i], a.zwins[i+1:]...)
-			break
-		}
+	if awin, ok := a.wins[id]; ok {
+		awin.win.BringToFront()
 	}
-
-	// Append it to the end of the slice.
-	a.zwins = append(a.zwins, awin)
 }
 
 // CreateWindow creates a new window with the given id, position and size.
@@ -126,7 +109,6 @@ func (a *App) CreateWindow(id string, x, y, width, height int) *AppWindow {
 		st:  style,
 	}
 	a.wins[id] = awin
-	a.zwins = append(a.zwins, awin)
 	return awin
 }
 
@@ -134,18 +116,13 @@ func (a *App) CreateWindow(id string, x, y, width, height int) *AppWindow {
 func (a *App) DestroyWindow(id string) {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
-	_, ok := a.wins[id]
+	awin, ok := a.wins[id]
 	if !ok {
 		return
 	}
 	log.Printf("destroying window %q", id)
 	delete(a.wins, id)
-	for i, zw := range a.zwins {
-		if zw.id == id {
-			a.zwins = append(a.zwins[:i], a.zwins[i+1:]...)
-			break
-		}
-	}
+	awin.win.Remove()
 	if a.active == id {
 		a.active = rootID
 	}
@@ -196,29 +173,31 @@ func (a *App) ParentID(id string) string {
 	return ""
 }
 
+// windowAt returns the top-most window at the given position, or nil if there
+// is none.
+func (a *App) windowAt(pos uv.Position) *AppWindow {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	win := a.root.win.WindowAt(pos)
+	if win == nil {
+		return nil
+	}
+	for _, aw := range a.wins {
+		if aw.win == win {
+			return aw
+		}
+	}
+	return nil
+}
+
 // Draw draws the applications windows to the root window.
 func (a *App) Draw(scr uv.Screen, area uv.Rectangle) {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
-	slices.SortStableFunc(a.zwins, func(aw1, aw2 *AppWindow) int {
-		return aw1.z - aw2.z
-	})
-
+	// The root window draws its child windows on top of itself in z order.
 	screen.Clear(a.root.win)
-	for _, zw := range a.zwins {
-		if zw.id == rootID {
-			continue
-		}
-		cell0 := zw.win.CellAt(0, 0)
-		var bg color.Color
-		if cell0 != nil {
-			bg = cell0.Style.Bg
-		}
-		log.Printf("drawing window %q at z-index %d bg color %v", zw.id, zw.z, bg)
-		zw.Draw(a.root.win, zw.Bounds())
-	}
-
 	a.root.Draw(scr, area)
 }
 
@@ -290,17 +269,12 @@ func (a *App) HandleEvent(id string, ev uv.Event) bool {
 		case uv.MouseLeft:
 			log.Printf("mouse left click for %q at (%d, %d)", id, ev.X, ev.Y)
 
-			for i := len(a.zwins) - 1; i >= 0; i-- {
-				zw := a.zwins[i]
-				pos := uv.Pos(ev.X, ev.Y)
-				bounds := zw.win.Bounds()
-				if zw.id != rootID && pos.In(bounds) {
-					log.Printf("clicked window %s at %v (bounds: %v)", zw.id, pos, bounds)
-					a.SetActiveID(zw.id)
-					a.BringToFront(zw.id)
-					a.lastClicked = zw.id
-					return true
-				}
+			if zw := a.windowAt(uv.Pos(ev.X, ev.Y)); zw != nil {
+				log.Printf("clicked window %s at (%d, %d) (bounds: %v)", zw.id, ev.X, ev.Y, zw.Bounds())
+				a.SetActiveID(zw.id)
+				a.BringToFront(zw.id)
+				a.lastClicked = zw.id
+				return true
 			}
 
 			log.Printf("no window clicked on at (%d, %d)", ev.X, ev.Y)
@@ -341,13 +315,9 @@ func (a *App) HandleEvent(id string, ev uv.Event) bool {
 
 		case uv.MouseRight:
 			// Destroy the clicked window.
-			for i := len(a.zwins) - 1; i >= 0; i-- {
-				zw := a.zwins[i]
-				pos := uv.Pos(ev.X, ev.Y)
-				if pos.In(zw.win.Bounds()) && zw.id != rootID {
-					log.Printf("right-clicked window %q at (%d, %d), destroying", zw.id, ev.X, ev.Y)
-					a.DestroyWindow(zw.id)
-				}
+			if zw := a.windowAt(uv.Pos(ev.X, ev.Y)); zw != nil {
+				log.Printf("right-clicked window %q at (%d, %d), destroying", zw.id, ev.X, ev.Y)
+				a.DestroyWindow(zw.id)
 			}
 
 			return true
@@ -380,7 +350,7 @@ func (a *App) Run(input uv.File, output uv.File, environ []string) error {
 
 	defer term.Stop()
 
-	scr.SetMouseMode(uv.MouseModeDrag)
+	term.SetMouseTracking(uv.MouseModeDrag)
 
 	for !a.quit {
 		select {
@@ -11,8 +11,6 @@ import (
 	"io"
 	"log"
 	"os"
-	"slices"
-	"strings"
 	"time"
 
 	_ "image/jpeg" // Register JPEG format
@@ -52,6 +50,20 @@ func (e imageEncoding) String() string {
 	}
 }
 
+// protocolEncoding returns the image encoding for the given image protocol.
+func protocolEncoding(p uv.ImageProtocol) imageEncoding {
+	switch p {
+	case uv.ImageProtocolKitty:
+		return kittyEncoding
+	case uv.ImageProtocolITerm2:
+		return itermEncoding
+	case uv.ImageProtocolSixel:
+		return sixelEncoding
+	default:
+		return blocksEncoding
+	}
+}
+
 var desiredEnc int
 
 func init() {
@@ -69,6 +81,15 @@ func main() {
 	flag.Parse()
 
 	t := uv.DefaultTerminal()
+
+	// Detect the most capable image protocol supported by the terminal. This
+	// probes the terminal, so it must happen before the terminal is started.
+	proto, err := uv.DetectImageProtocol(t)
+	if err != nil {
+		log.Printf("failed to probe terminal: %v", err)
+	}
+	log.Printf("detected image protocol: %v", proto)
+
 	if err := t.Start(); err != nil {
 		log.Fatalf("failed to start terminal: %v", err)
 	}
@@ -81,7 +102,7 @@ func main() {
 	scr.EnterAltScreen() //nolint:errcheck
 
 	// Enable mouse support.
-	scr.SetMouseMode(uv.MouseModeClick)
+	t.SetMouseTracking(uv.MouseModeClick)
 
 	// Get image info.
 	charmImgFile, err := os.Open("./charm.jpg")
@@ -111,7 +132,7 @@ func main() {
 	var (
 		winSize uv.WindowSizeEvent
 		pixSize uv.PixelSizeEvent
-		imgEnc  = blocksEncoding
+		imgEnc  = protocolEncoding(proto)
 	)
 	if desiredEnc > 0 {
 		imgEnc = imageEncoding(desiredEnc)
@@ -119,39 +140,6 @@ func main() {
 
 	scr.Resize(winSize.Width, winSize.Height) //nolint:errcheck
 
-	upgradeEnc := func(enc imageEncoding) {
-		if desiredEnc == unknownEncoding {
-			if enc > imgEnc {
-				imgEnc = enc
-			}
-		}
-	}
-
-	// Check environment variables for supported encodings.
-	var (
-		termType    = os.Getenv("TERM")
-		termProg    string
-		lcTerm      string
-		termVersion string
-		ok          bool
-	)
-	if termProg, ok = os.LookupEnv("TERM_PROGRAM"); ok {
-		if strings.Contains(termProg, "iTerm") ||
-			strings.Contains(termProg, "WezTerm") ||
-			strings.Contains(termProg, "mintty") ||
-			strings.Contains(termProg, "vscode") ||
-			strings.Contains(termProg, "Tabby") ||
-			strings.Contains(termProg, "Hyper") ||
-			strings.Contains(termProg, "rio") {
-			upgradeEnc(itermEncoding)
-		}
-		if lcTerm, ok = os.LookupEnv("LC_TERMINAL"); ok {
-			if strings.Contains(lcTerm, "iTerm") {
-				upgradeEnc(itermEncoding)
-			}
-		}
-	}
-
 	// Display image methods.
 	imgCellSize := func() (int, int) {
 		if winSize.Width == 0 || winSize.Height == 0 || pixSize.Width == 0 || pixSize.Height == 0 {
@@ -302,12 +290,8 @@ func main() {
 		scr.Flush()
 	}
 
-	// Query image encoding support.
-	scr.WriteString(ansi.RequestPrimaryDeviceAttributes)        // Query Sixel support.
-	scr.WriteString(ansi.RequestNameVersion)                    // Query terminal version and name.
-	scr.WriteString(ansi.WindowOp(ansi.RequestWindowSizeWinOp)) // Request window size.
-	// Query Kitty Graphics support using random id=31.
-	scr.WriteString(ansi.KittyGraphics([]byte("AAAA"), "i=31", "s=1", "v=1", "a=q", "t=d", "f=24"))
+	// Request the window size in pixels.
+	scr.WriteString(ansi.WindowOp(ansi.RequestWindowSizeWinOp))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -358,36 +342,12 @@ LOOP:
 				imgOffsetY = ev.Y - (imgCellH / 2)
 
 				displayImg()
-			case uv.PrimaryDeviceAttributesEvent:
-				if slices.Contains(ev, 4) {
-					upgradeEnc(sixelEncoding)
-					displayImg()
-				}
-
-			case uv.TerminalVersionEvent:
-				if strings.Contains(ev.Name, "iTerm") || strings.Contains(ev.Name, "WezTerm") {
-					upgradeEnc(itermEncoding)
-					displayImg()
-				}
-
 			case uv.WindowOpEvent:
 				// Here 4 corresponds to the window size response.
 				if ev.Op == 4 && len(ev.Args) >= 2 {
 					pixSize.Height = ev.Args[0]
 					pixSize.Width = ev.Args[1]
 				}
-			case uv.KittyGraphicsEvent:
-				if strings.Contains(termType, "wezterm") ||
-					strings.Contains(termVersion, "WezTerm") ||
-					strings.Contains(termProg, "WezTerm") {
-					// WezTerm doesn't support Kitty Unicode Graphics
-					break
-				}
-				if ev.Options.ID == 31 {
-					upgradeEnc(kittyEncoding)
-				}
-
-				displayImg()
 			}
 		}
 	}
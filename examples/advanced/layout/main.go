@@ -343,7 +343,7 @@ func main() {
 	}
 
 	scr.EnterAltScreen()
-	scr.WriteString(ansi.SetMode(ansi.ModeMouseButtonEvent, ansi.ModeMouseExtSgr))
+	t.SetMouseTracking(uv.MouseModeDrag)
 
 	dialogWidth := lipgloss.Width(dialogUI) + dialogBoxStyle.GetHorizontalFrameSize()
 	dialogHeight := lipgloss.Height(dialogUI) + dialogBoxStyle.GetVerticalFrameSize()
@@ -18,6 +18,8 @@ func main() {
 
 	// Start in altscreen mode
 	scr.EnterAltScreen()
+	t.EnableFocusReporting()
+	t.SetMouseTracking(uv.MouseModeDrag)
 
 	if err := t.Start(); err != nil {
 		log.Fatalf("failed to start program: %v", err)
@@ -25,14 +27,6 @@ func main() {
 
 	defer t.Stop()
 
-	modes := []ansi.Mode{
-		ansi.ButtonEventMouseMode,
-		ansi.SgrExtMouseMode,
-		ansi.FocusEventMode,
-	}
-
-	scr.WriteString(ansi.SetMode(modes...))
-
 	// Listen for input and mouse events.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -198,6 +192,4 @@ LOOP:
 			}
 		}
 	}
-
-	scr.WriteString(ansi.ResetMode(modes...))
 }
@@ -80,11 +80,7 @@ func run(t *uv.Terminal) error {
 				}
 				display()
 			case ev.MatchString("ctrl+z"):
-				_ = t.Stop()
-
-				uv.Suspend()
-
-				_ = t.Start()
+				_ = t.Suspend()
 			default:
 				return nil
 			}
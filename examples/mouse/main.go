@@ -23,8 +23,8 @@ func main() {
 
 	defer t.Stop()
 
-	scr.SetMouseMode(uv.MouseModeMotion)
 	scr.SetMouseEncoding(uv.MouseEncodingSGRPixel)
+	t.SetMouseTracking(uv.MouseModeMotion)
 
 	var lastBtn uv.MouseButton
 	var lastX, lastY int
@@ -36,9 +36,7 @@ func main() {
 		var st uv.Style
 		st.Bg = ansi.BasicColor(4)
 		st.Fg = ansi.Black
-		bg := uv.EmptyCell
-		bg.Style = st
-		screen.FillArea(scr, &bg, uv.Rect(0, 0, width, 1))
+		screen.FillStyle(scr, uv.Rect(0, 0, width, 1), st)
 		for i, r := range label {
 			scr.SetCell(i, 0, &uv.Cell{
 				Content: string(r),
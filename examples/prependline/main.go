@@ -20,7 +20,9 @@ func main() {
 
 	defer t.Stop()
 
-	scr.WriteString(ansi.SetWindowTitle("Hello, World!"))
+	// Restore the previous title on exit.
+	t.PushTitle("Hello, World!")
+	defer t.PopTitle()
 
 	var st uv.Style
 	bg := 1
@@ -29,9 +31,7 @@ func main() {
 
 	display := func() {
 		const hw = "Hello, World!"
-		bg := uv.EmptyCell
-		bg.Style = st
-		screen.FillArea(scr, &bg, uv.Rect(0, 0, scr.Bounds().Dx(), 1))
+		screen.FillStyle(scr, uv.Rect(0, 0, scr.Bounds().Dx(), 1), st)
 		for i, r := range hw {
 			scr.SetCell(i, 0, &uv.Cell{
 				Content: string(r),
@@ -69,6 +69,4 @@ func main() {
 		st.Bg = ansi.BasicColor(rd)
 		display()
 	}
-
-	scr.WriteString(ansi.SetWindowTitle(""))
 }
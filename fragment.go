@@ -0,0 +1,169 @@
+package uv
+
+// FragmentKind is the kind of a [Fragment] node.
+type FragmentKind uint8
+
+// Fragment kinds.
+const (
+	// BoxFragment is a container node. It fills its area with blank cells
+	// using its style, then draws its children on top.
+	BoxFragment FragmentKind = iota
+	// TextFragment is a leaf node that draws its text as a [StyledString].
+	// Its children are ignored.
+	TextFragment
+)
+
+// Fragment is a node of a retained component tree. Instead of drawing the
+// whole UI on every frame, a tree of fragments is kept around and updated
+// with [Fragment.Reconcile], which returns the areas that changed. Only those
+// areas need to be redrawn with [Fragment.Redraw].
+//
+// Children are identified by their [Fragment.Key]. When the keys of the
+// children of a node don't match the ones of the next tree, in the same
+// order, the whole node is redrawn.
+type Fragment struct {
+	// Key identifies the node among its siblings.
+	Key string
+	// Kind is the kind of the node.
+	Kind FragmentKind
+	// Area is the area of the node, relative to its parent area. Nodes are
+	// clipped to the area of their parent.
+	Area Rectangle
+	// Text is the content of a [TextFragment]. It can contain SGR and
+	// hyperlink escape codes.
+	Text string
+	// Style is the style used to fill a [BoxFragment].
+	Style Style
+	// Children are the child nodes of a [BoxFragment], drawn in order.
+	Children []*Fragment
+}
+
+var _ Drawable = (*Fragment)(nil)
+
+// NewBoxFragment returns a new [BoxFragment] node with the given key, area, and
+// children.
+func NewBoxFragment(key string, area Rectangle, children ...*Fragment) *Fragment {
+	return &Fragment{Key: key, Kind: BoxFragment, Area: area, Children: children}
+}
+
+// NewTextFragment returns a new [TextFragment] node with the given key, area,
+// and text.
+func NewTextFragment(key string, area Rectangle, text string) *Fragment {
+	return &Fragment{Key: key, Kind: TextFragment, Area: area, Text: text}
+}
+
+// Draw draws the node and its children. The node area is relative to the
+// given area.
+func (f *Fragment) Draw(scr Screen, area Rectangle) {
+	f.draw(scr, area, area)
+}
+
+// Redraw draws the parts of the node that fall in the damaged areas returned
+// by [Fragment.Reconcile]. The area is the same as the one given to
+// [Fragment.Draw].
+func (f *Fragment) Redraw(scr Screen, area Rectangle, damage []Rectangle) {
+	for _, r := range damage {
+		clip := r.Add(area.Min).Intersect(area)
+		if clip.Empty() {
+			continue
+		}
+		f.draw(&clipScreen{Screen: scr, clip: clip}, area, clip)
+	}
+}
+
+// draw draws the node in the parent area, skipping it when it's outside of
+// the clip area.
+func (f *Fragment) draw(scr Screen, parent, clip Rectangle) {
+	area := f.Area.Add(parent.Min).Intersect(parent)
+	if area.Empty() || area.Intersect(clip).Empty() {
+		return
+	}
+
+	switch f.Kind {
+	case BoxFragment:
+		cell := EmptyCell
+		cell.Style = f.Style
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			for x := area.Min.X; x < area.Max.X; x++ {
+				scr.SetCell(x, y, &cell)
+			}
+		}
+		for _, c := range f.Children {
+			if c != nil {
+				c.draw(scr, area, clip)
+			}
+		}
+	case TextFragment:
+		NewStyledString(f.Text).Draw(scr, area)
+	}
+}
+
+// Reconcile updates the node to match next and returns the areas that need
+// to be redrawn, relative to the parent area of the node, the same as
+// [Fragment.Area].
+//
+// Nodes with the same key and kind are updated in place, so the tree keeps
+// its identity across updates. A node is redrawn entirely when its key, kind,
+// area, text, or style changed, or when the keys of its children don't match
+// the ones of next. Otherwise, only the changes of its children are returned.
+func (f *Fragment) Reconcile(next *Fragment) []Rectangle {
+	if next == nil {
+		return nil
+	}
+
+	if f.Key != next.Key || f.Kind != next.Kind || f.Area != next.Area {
+		damage := []Rectangle{f.Area, next.Area}
+		*f = *next
+		return nonEmpty(damage)
+	}
+
+	if f.Text != next.Text || !f.Style.Equal(&next.Style) || !sameKeys(f.Children, next.Children) {
+		*f = *next
+		return nonEmpty([]Rectangle{f.Area})
+	}
+
+	var damage []Rectangle
+	for i, c := range f.Children {
+		if c == nil {
+			continue
+		}
+		for _, r := range c.Reconcile(next.Children[i]) {
+			// Child areas are relative to this node and clipped to it.
+			if r = r.Add(f.Area.Min).Intersect(f.Area); !r.Empty() {
+				damage = append(damage, r)
+			}
+		}
+	}
+	return damage
+}
+
+// sameKeys reports whether both lists of nodes have the same keys in the same
+// order.
+func sameKeys(a, b []*Fragment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] == nil || b[i] == nil {
+			if a[i] != b[i] {
+				return false
+			}
+			continue
+		}
+		if a[i].Key != b[i].Key {
+			return false
+		}
+	}
+	return true
+}
+
+// nonEmpty returns the non-empty rectangles of rs.
+func nonEmpty(rs []Rectangle) []Rectangle {
+	out := rs[:0]
+	for _, r := range rs {
+		if !r.Empty() {
+			out = append(out, r)
+		}
+	}
+	return out
+}
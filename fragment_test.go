@@ -0,0 +1,104 @@
+package uv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFragmentReconcile(t *testing.T) {
+	tree := func(title, body string) *Fragment {
+		return NewBoxFragment("root", Rect(0, 0, 6, 3),
+			NewTextFragment("title", Rect(0, 0, 6, 1), title),
+			NewBoxFragment("body", Rect(1, 1, 5, 2),
+				NewTextFragment("text", Rect(0, 0, 5, 1), body),
+			),
+		)
+	}
+
+	cases := []struct {
+		name     string
+		next     *Fragment
+		damage   []Rectangle
+		expected string
+	}{
+		{
+			name:     "unchanged",
+			next:     tree("title", "body"),
+			expected: "title\n body\n",
+		},
+		{
+			name:     "text changed",
+			next:     tree("title", "text"),
+			damage:   []Rectangle{Rect(1, 1, 5, 1)},
+			expected: "title\n text\n",
+		},
+		{
+			name: "style changed",
+			next: func() *Fragment {
+				f := tree("title", "text")
+				f.Children[1].Style = Style{Fg: ansi.Red}
+				return f
+			}(),
+			damage:   []Rectangle{Rect(1, 1, 5, 2)},
+			expected: "title\n text\n      ",
+		},
+		{
+			name: "area changed",
+			next: NewBoxFragment("root", Rect(0, 0, 6, 3),
+				NewTextFragment("title", Rect(1, 0, 5, 1), "title"),
+				NewBoxFragment("body", Rect(1, 1, 5, 2),
+					NewTextFragment("text", Rect(0, 0, 5, 1), "text"),
+				),
+			),
+			// The body style is back to the default.
+			damage:   []Rectangle{Rect(0, 0, 6, 1), Rect(1, 0, 5, 1), Rect(1, 1, 5, 2)},
+			expected: " title\n text\n",
+		},
+		{
+			name: "keys don't match",
+			next: NewBoxFragment("root", Rect(0, 0, 6, 3),
+				NewTextFragment("other", Rect(0, 0, 6, 1), "x"),
+			),
+			damage:   []Rectangle{Rect(0, 0, 6, 3)},
+			expected: "x\n\n",
+		},
+	}
+
+	root := tree("title", "body")
+	scr := NewScreenBuffer(6, 3)
+	root.Draw(scr, scr.Bounds())
+	for _, tc := range cases {
+		damage := root.Reconcile(tc.next)
+		if !reflect.DeepEqual(tc.damage, damage) {
+			t.Errorf("%s: expected damage %v, got %v", tc.name, tc.damage, damage)
+		}
+		root.Redraw(scr, scr.Bounds(), damage)
+		if got := scr.String(); got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestFragmentRedrawClipped(t *testing.T) {
+	root := NewBoxFragment("root", Rect(0, 0, 4, 1),
+		NewTextFragment("a", Rect(0, 0, 2, 1), "aa"),
+		NewTextFragment("b", Rect(2, 0, 2, 1), "bb"),
+	)
+	scr := NewScreenBuffer(6, 2)
+	area := Rect(1, 1, 4, 1)
+	root.Draw(scr, area)
+
+	// Only the damaged area is drawn again.
+	scr.SetCell(1, 1, &Cell{Content: "x", Width: 1})
+	next := NewBoxFragment("root", Rect(0, 0, 4, 1),
+		NewTextFragment("a", Rect(0, 0, 2, 1), "aa"),
+		NewTextFragment("b", Rect(2, 0, 2, 1), "cc"),
+	)
+	root.Redraw(scr, area, root.Reconcile(next))
+	expected := "\n xacc"
+	if got := scr.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
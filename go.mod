@@ -16,7 +16,10 @@ require (
 	golang.org/x/sys v0.46.0
 )
 
-require github.com/clipperhouse/displaywidth v0.11.0 // indirect
+require (
+	github.com/bits-and-blooms/bitset v1.24.4 // indirect
+	github.com/clipperhouse/displaywidth v0.11.0 // indirect
+)
 
 require (
 	github.com/lucasb-eyer/go-colorful v1.4.0
@@ -0,0 +1,136 @@
+package uv
+
+import "slices"
+
+// MouseEnterEvent is sent by a [HoverTracker] when the mouse pointer enters a
+// region.
+type MouseEnterEvent struct {
+	// ID is the ID of the region the pointer entered.
+	ID string
+	// X and Y are the position of the pointer.
+	X, Y int
+}
+
+// MouseLeaveEvent is sent by a [HoverTracker] when the mouse pointer leaves a
+// region.
+type MouseLeaveEvent struct {
+	// ID is the ID of the region the pointer left.
+	ID string
+	// X and Y are the position of the pointer.
+	X, Y int
+}
+
+// MouseHoverEvent is sent by a [HoverTracker] when the mouse pointer moves
+// within the region it's hovering.
+type MouseHoverEvent struct {
+	// ID is the ID of the hovered region.
+	ID string
+	// X and Y are the position of the pointer.
+	X, Y int
+}
+
+// hoverRegion is a region registered in a [HoverTracker].
+type hoverRegion struct {
+	id   string
+	area Rectangle
+}
+
+// HoverTracker keeps track of the region under the mouse pointer and
+// synthesizes [MouseEnterEvent], [MouseLeaveEvent], and [MouseHoverEvent]
+// events as the pointer moves across regions. This is useful for hover states
+// and tooltips.
+//
+// Regions are identified by an ID and can overlap, in which case the region
+// that was registered last is the one that's hovered. Mouse motion is only
+// reported by the terminal when motion tracking is enabled, see
+// [Terminal.SetMouseTracking] and [MouseModeMotion].
+//
+// The zero value is ready to use.
+type HoverTracker struct {
+	regions []hoverRegion
+	hovered string
+	inside  bool
+	x, y    int
+}
+
+// SetRegion registers a region with the given ID and area. If a region with
+// the same ID exists, its area is updated and it keeps its stacking order.
+func (h *HoverTracker) SetRegion(id string, area Rectangle) {
+	if i := h.index(id); i >= 0 {
+		h.regions[i].area = area
+		return
+	}
+	h.regions = append(h.regions, hoverRegion{id: id, area: area})
+}
+
+// RemoveRegion removes the region with the given ID.
+func (h *HoverTracker) RemoveRegion(id string) {
+	if i := h.index(id); i >= 0 {
+		h.regions = slices.Delete(h.regions, i, i+1)
+	}
+}
+
+// ClearRegions removes all regions.
+func (h *HoverTracker) ClearRegions() {
+	h.regions = h.regions[:0]
+}
+
+// Hovered returns the ID of the region under the mouse pointer as of the last
+// [HoverTracker.Update] call, and whether there is one.
+func (h *HoverTracker) Hovered() (id string, ok bool) {
+	return h.hovered, h.inside
+}
+
+// Update feeds an event to the tracker and returns the synthesized events.
+// Only [MouseEvent] events are used to track the pointer position, other
+// events are ignored and return nil.
+//
+// When the pointer moves to another region, a [MouseLeaveEvent] for the old
+// region is returned before the [MouseEnterEvent] for the new one. When it
+// moves within the hovered region, a [MouseHoverEvent] is returned.
+func (h *HoverTracker) Update(ev Event) []Event {
+	mev, ok := ev.(MouseEvent)
+	if !ok {
+		return nil
+	}
+
+	m := mev.Mouse()
+	id, inside := h.regionAt(Pos(m.X, m.Y))
+	moved := m.X != h.x || m.Y != h.y
+	h.x, h.y = m.X, m.Y
+
+	var events []Event
+	switch {
+	case inside && h.inside && id == h.hovered:
+		if moved {
+			events = append(events, MouseHoverEvent{ID: id, X: m.X, Y: m.Y})
+		}
+	default:
+		if h.inside {
+			events = append(events, MouseLeaveEvent{ID: h.hovered, X: m.X, Y: m.Y})
+		}
+		if inside {
+			events = append(events, MouseEnterEvent{ID: id, X: m.X, Y: m.Y})
+		}
+	}
+
+	h.hovered, h.inside = id, inside
+	return events
+}
+
+// regionAt returns the ID of the top-most region that contains the given
+// position.
+func (h *HoverTracker) regionAt(p Position) (string, bool) {
+	for i := len(h.regions) - 1; i >= 0; i-- {
+		if p.In(h.regions[i].area) {
+			return h.regions[i].id, true
+		}
+	}
+	return "", false
+}
+
+func (h *HoverTracker) index(id string) int {
+	return slices.IndexFunc(h.regions, func(r hoverRegion) bool {
+		return r.id == id
+	})
+}
@@ -0,0 +1,78 @@
+package uv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHoverTracker(t *testing.T) {
+	var h HoverTracker
+	h.SetRegion("a", Rect(0, 0, 5, 5))
+	h.SetRegion("b", Rect(3, 3, 5, 5))
+
+	cases := []struct {
+		name     string
+		event    Event
+		expected []Event
+		hovered  string
+	}{
+		{
+			name:     "enter",
+			event:    MouseMotionEvent{X: 1, Y: 1},
+			expected: []Event{MouseEnterEvent{ID: "a", X: 1, Y: 1}},
+			hovered:  "a",
+		},
+		{
+			name:     "hover",
+			event:    MouseMotionEvent{X: 2, Y: 1},
+			expected: []Event{MouseHoverEvent{ID: "a", X: 2, Y: 1}},
+			hovered:  "a",
+		},
+		{
+			name:    "same position",
+			event:   MouseClickEvent{X: 2, Y: 1, Button: MouseLeft},
+			hovered: "a",
+		},
+		{
+			name:  "overlapping region on top",
+			event: MouseMotionEvent{X: 4, Y: 4},
+			expected: []Event{
+				MouseLeaveEvent{ID: "a", X: 4, Y: 4},
+				MouseEnterEvent{ID: "b", X: 4, Y: 4},
+			},
+			hovered: "b",
+		},
+		{
+			name:     "leave",
+			event:    MouseMotionEvent{X: 9, Y: 0},
+			expected: []Event{MouseLeaveEvent{ID: "b", X: 9, Y: 0}},
+		},
+		{
+			name:  "outside",
+			event: MouseMotionEvent{X: 9, Y: 1},
+		},
+		{
+			name:  "not a mouse event",
+			event: KeyPressEvent{Code: 'a'},
+		},
+	}
+
+	for _, tc := range cases {
+		got := h.Update(tc.event)
+		if !reflect.DeepEqual(tc.expected, got) {
+			t.Errorf("%s: expected events %v, got %v", tc.name, tc.expected, got)
+		}
+		id, ok := h.Hovered()
+		if id != tc.hovered || ok != (tc.hovered != "") {
+			t.Errorf("%s: expected hovered region %q, got %q (%v)", tc.name, tc.hovered, id, ok)
+		}
+	}
+
+	// Regions are looked up again on the next update.
+	h.RemoveRegion("b")
+	h.SetRegion("a", Rect(0, 0, 10, 10))
+	expected := []Event{MouseEnterEvent{ID: "a", X: 9, Y: 2}}
+	if got := h.Update(MouseMotionEvent{X: 9, Y: 2}); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected events %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,17 @@
+// Package text provides text helpers shared by the editing components.
+package text
+
+import "github.com/clipperhouse/uax29/v2/graphemes"
+
+// Graphemes splits s into grapheme clusters, dropping control characters.
+func Graphemes(s string) (grs []string) {
+	iter := graphemes.FromString(s)
+	for iter.Next() {
+		gr := iter.Value()
+		if r := gr[0]; r < 0x20 || r == 0x7f {
+			continue
+		}
+		grs = append(grs, gr)
+	}
+	return
+}
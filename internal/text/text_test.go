@@ -0,0 +1,25 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemes(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"ab", []string{"a", "b"}},
+		{"é你", []string{"é", "你"}},
+		{"👩‍💻!", []string{"👩‍💻", "!"}},
+		{"a\tb\x1b\x7fc\n", []string{"a", "b", "c"}},
+	}
+
+	for _, tc := range cases {
+		if got := Graphemes(tc.input); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("expected %q for %q, got %q", tc.expected, tc.input, got)
+		}
+	}
+}
@@ -305,6 +305,11 @@ type Key struct {
 	// This is only available with the Kitty Keyboard Protocol or the Windows
 	// Console API.
 	IsRepeat bool
+
+	// RepeatCount is the number of identical key presses that were coalesced
+	// into this event. It's zero unless repeat coalescing is enabled using
+	// [TerminalReader.SetRepeatCoalesce], in which case it's at least one.
+	RepeatCount int
 }
 
 // MatchString returns true if the [Key] matches one of the given strings.
@@ -382,6 +387,16 @@ func keyMatchString(k Key, s string) bool {
 		(k.Text != "" && k.Text == text)
 }
 
+// IsModifierOnly reports whether the [Key] is a lone modifier key press, like
+// [KeyLeftCtrl] or [KeyRightShift], that doesn't produce any text. This
+// includes the ISO level 3 and level 5 shift keys.
+//
+// Lone modifier keys are only reported with the Kitty Keyboard Protocol or
+// the Windows Console API.
+func (k Key) IsModifierOnly() bool {
+	return k.Code >= KeyLeftShift && k.Code <= KeyIsoLevel5Shift && len(k.Text) == 0
+}
+
 // String implements [fmt.Stringer] and is quite useful for matching key
 // events. It will return the textual representation of the [Key] if there is
 // one, otherwise, it will fallback to [Key.Keystroke].
@@ -377,6 +377,30 @@ func TestParseSequence(t *testing.T) {
 			},
 		},
 
+		// Kitty associated text with multiple codepoints.
+		seqTest{
+			[]byte("\x1b[97;;229:230u"),
+			[]Event{KeyPressEvent{Code: 'a', Text: "åæ"}},
+		},
+		seqTest{
+			[]byte("\x1b[97;1;229:230:231u"),
+			[]Event{KeyPressEvent{Code: 'a', Text: "åæç"}},
+		},
+
+		// Kitty alternate keys with shifted and base layout codepoints.
+		seqTest{
+			[]byte("\x1b[97:65;2;65u"),
+			[]Event{KeyPressEvent{Code: 'a', ShiftedCode: 'A', Text: "A", Mod: ModShift}},
+		},
+		seqTest{
+			[]byte("\x1b[1089:1057:99;2;1057u"),
+			[]Event{KeyPressEvent{Code: 'с', ShiftedCode: 'С', BaseCode: 'c', Text: "С", Mod: ModShift}},
+		},
+		seqTest{
+			[]byte("\x1b[1089::99;5u"),
+			[]Event{KeyPressEvent{Code: 'с', BaseCode: 'c', Mod: ModCtrl}},
+		},
+
 		// Kitty NumPad keys.
 		seqTest{
 			[]byte("\x1b[57399u" +
@@ -828,14 +852,14 @@ func TestSplitReads(t *testing.T) {
 		KeyPressEvent{Code: 'b', Text: "b"},
 		KeyPressEvent{Code: 'c', Text: "c"},
 		KeyPressEvent{Code: KeyUp},
-		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft},
+		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft, Count: 1},
 		FocusEvent{},
-		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft},
+		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft, Count: 2},
 		BlurEvent{},
-		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft},
+		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft, Count: 3},
 		KeyPressEvent{Code: KeyUp},
-		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft},
-		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft},
+		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft, Count: 1},
+		MouseClickEvent{X: 32, Y: 16, Button: MouseLeft, Count: 2},
 		FocusEvent{},
 		UnknownEvent("\x1b[12;34;9"),
 	}
@@ -877,6 +901,8 @@ func TestSplitReads(t *testing.T) {
 	r := LimitedReader(strings.NewReader(strings.Join(inputs, "")), 8)
 	drv := NewTerminalReader(r, "dumb")
 	drv.SetLogger(TLogger{t})
+	// Keep the click counts independent of how long the reads take.
+	drv.eventScanner.now = func() time.Time { return time.Time{} }
 
 	eventc := make(chan Event)
 	go func(t testing.TB) {
@@ -896,6 +922,107 @@ func TestSplitReads(t *testing.T) {
 	}
 }
 
+func TestSplitRunes(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  string
+		expect []Event
+	}{
+		{
+			name:   "three bytes",
+			input:  "☃",
+			expect: []Event{KeyPressEvent{Code: '☃', Text: "☃"}},
+		},
+		{
+			name:  "four bytes",
+			input: "a🎉b",
+			expect: []Event{
+				KeyPressEvent{Code: 'a', Text: "a"},
+				KeyPressEvent{Code: '🎉', Text: "🎉"},
+				KeyPressEvent{Code: 'b', Text: "b"},
+			},
+		},
+		{
+			name:  "invalid",
+			input: "\xe2a",
+			expect: []Event{
+				UnknownEvent(rune(0xe2)),
+				KeyPressEvent{Code: 'a', Text: "a"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Feed the input one byte at a time.
+			r := LimitedReader(strings.NewReader(tc.input), 1)
+			drv := NewTerminalReader(r, "dumb")
+
+			eventc := make(chan Event)
+			go func(t testing.TB) {
+				defer close(eventc)
+				if err := drv.StreamEvents(t.Context(), eventc); err != nil {
+					t.Errorf("error streaming events: %v", err)
+				}
+			}(t)
+
+			var events []Event
+			for ev := range eventc {
+				events = append(events, ev)
+			}
+
+			if !reflect.DeepEqual(tc.expect, events) {
+				t.Errorf("unexpected messages, expected:\n    %+v\ngot:\n    %+v", tc.expect, events)
+			}
+		})
+	}
+}
+
+func TestClickCount(t *testing.T) {
+	var now time.Time
+	evs := newEventScanner()
+	evs.now = func() time.Time { return now }
+
+	cases := []struct {
+		name  string
+		after time.Duration
+		input string
+		count int
+	}{
+		{name: "single", input: "\x1b[<0;5;5M", count: 1},
+		{name: "double", after: 100 * time.Millisecond, input: "\x1b[<0;5;5M", count: 2},
+		{name: "triple nearby", after: 100 * time.Millisecond, input: "\x1b[<0;6;5M", count: 3},
+		{name: "starts over", after: 100 * time.Millisecond, input: "\x1b[<0;6;5M", count: 1},
+		{name: "too slow", after: time.Second, input: "\x1b[<0;6;5M", count: 1},
+		{name: "too far", after: 100 * time.Millisecond, input: "\x1b[<0;8;5M", count: 1},
+		{name: "other button", after: 100 * time.Millisecond, input: "\x1b[<2;8;5M", count: 1},
+	}
+
+	for _, tc := range cases {
+		now = now.Add(tc.after)
+		_, events := evs.scanEvents([]byte(tc.input), false)
+		if len(events) != 1 {
+			t.Fatalf("%s: expected a single event, got %#v", tc.name, events)
+		}
+		c, ok := events[0].(MouseClickEvent)
+		if !ok {
+			t.Fatalf("%s: expected a click event, got %#v", tc.name, events[0])
+		}
+		if c.Count != tc.count {
+			t.Errorf("%s: expected click count %d, got %d", tc.name, tc.count, c.Count)
+		}
+	}
+
+	// A negative interval reports every click as a single click.
+	evs.clickInterval = -1
+	for range 2 {
+		_, events := evs.scanEvents([]byte("\x1b[<0;5;5M"), false)
+		if c, ok := events[0].(MouseClickEvent); !ok || c.Count != 1 {
+			t.Errorf("expected a single click, got %#v", events[0])
+		}
+	}
+}
+
 func TestReadLongInput(t *testing.T) {
 	expect := make([]Event, 1000)
 	for i := 0; i < 1000; i++ {
@@ -1168,7 +1295,7 @@ func TestReadInput(t *testing.T) {
 			},
 			[]Event{
 				PasteStartEvent{},
-				PasteEvent{"a b"},
+				PasteEvent{Content: "a b"},
 				PasteEndEvent{},
 				KeyPressEvent{Code: 'o', Text: "o"},
 			},
@@ -1182,7 +1309,7 @@ func TestReadInput(t *testing.T) {
 			},
 			[]Event{
 				PasteStartEvent{},
-				PasteEvent{"a\x03\nb"},
+				PasteEvent{Content: "a\x03\nb"},
 				PasteEndEvent{},
 			},
 		},
@@ -2252,11 +2379,12 @@ func TestKeyMatchString(t *testing.T) {
 // when split across multiple read() calls.
 func TestSplitSequences(t *testing.T) {
 	tests := []struct {
-		name   string
-		chunks [][]byte
-		want   []Event
-		delay  time.Duration
-		limit  int // limit the number of bytes read at once
+		name       string
+		chunks     [][]byte
+		want       []Event
+		delay      time.Duration
+		limit      int           // limit the number of bytes read at once
+		escTimeout time.Duration // the reader escape timeout, if set
 	}{
 		{
 			name: "OSC 11 background color with ST terminator",
@@ -2373,6 +2501,19 @@ func TestSplitSequences(t *testing.T) {
 			},
 			delay: 60 * time.Millisecond, // Ensure the timeout is triggered.
 		},
+		{
+			name: "slow sequence with longer timeout",
+			chunks: [][]byte{
+				[]byte("\x1b]11;rgb:1111/2222/3333"),
+				[]byte("\x07a"),
+			},
+			want: []Event{
+				BackgroundColorEvent{Color: ansi.XParseColor("rgb:1111/2222/3333")},
+				KeyPressEvent{Code: 'a', Text: "a"},
+			},
+			delay:      60 * time.Millisecond,
+			escTimeout: 200 * time.Millisecond,
+		},
 		{
 			name: "multiple broken down sequences",
 			chunks: [][]byte{
@@ -2448,6 +2589,9 @@ func TestSplitSequences(t *testing.T) {
 			}
 			ir := NewTerminalReader(r, "xterm-256color")
 			ir.SetLogger(TLogger{TB: t})
+			if tt.escTimeout > 0 {
+				ir.SetEscTimeout(tt.escTimeout)
+			}
 
 			eventc := make(chan Event)
 			go func(t testing.TB) {
@@ -2744,3 +2888,238 @@ func TestKeyStringMore(t *testing.T) {
 		})
 	}
 }
+
+func TestDisambiguatedKeys(t *testing.T) {
+	// Pairs of keys that share the same legacy encoding but are reported
+	// distinctly when the Kitty disambiguate enhancement is enabled.
+	cases := []struct {
+		name   string
+		legacy string
+		seq    string
+		want   string
+		// legacyWant is what both keys of a pair decode to without the
+		// enhancement.
+		legacyWant string
+	}{
+		{"tab", "\t", "\x1b[9u", "tab", "tab"},
+		{"ctrl+i", "\t", "\x1b[105;5u", "ctrl+i", "tab"},
+		{"enter", "\r", "\x1b[13u", "enter", "enter"},
+		{"ctrl+m", "\r", "\x1b[109;5u", "ctrl+m", "enter"},
+		{"esc", "\x1b", "\x1b[27u", "esc", "esc"},
+		{"ctrl+[", "\x1b", "\x1b[91;5u", "ctrl+[", "esc"},
+	}
+
+	var p EventDecoder
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, e := p.Decode([]byte(tc.seq))
+			k, ok := e.(KeyPressEvent)
+			if !ok {
+				t.Fatalf("expected KeyPressEvent for %q, got %T", tc.seq, e)
+			}
+			if got := k.String(); got != tc.want {
+				t.Errorf("expected %q for %q, got %q", tc.want, tc.seq, got)
+			}
+
+			_, le := p.Decode([]byte(tc.legacy))
+			lk, ok := le.(KeyPressEvent)
+			if !ok {
+				t.Fatalf("expected KeyPressEvent for %q, got %T", tc.legacy, le)
+			}
+			if got := lk.String(); got != tc.legacyWant {
+				t.Errorf("expected %q for %q, got %q", tc.legacyWant, tc.legacy, got)
+			}
+		})
+	}
+}
+
+func TestReadRepeatCoalesce(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		delay  time.Duration
+		want   []Event
+	}{
+		{
+			name:   "coalesced",
+			window: time.Hour,
+			want: []Event{
+				KeyPressEvent{Code: KeyUp, RepeatCount: 3},
+				KeyPressEvent{Code: KeyDown, RepeatCount: 1},
+				KeyPressEvent{Code: 'x', Text: "x", RepeatCount: 1},
+			},
+		},
+		{
+			name:   "window elapsed",
+			window: time.Millisecond,
+			delay:  20 * time.Millisecond,
+			want: []Event{
+				KeyPressEvent{Code: KeyUp, RepeatCount: 1},
+				KeyPressEvent{Code: KeyUp, RepeatCount: 1},
+				KeyPressEvent{Code: KeyUp, RepeatCount: 1},
+				KeyPressEvent{Code: KeyDown, RepeatCount: 1},
+				KeyPressEvent{Code: 'x', Text: "x", RepeatCount: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := strings.NewReader("\x1b[A\x1b[A\x1b[A\x1b[Bx")
+			var r io.Reader
+			if tt.delay > 0 {
+				r = DelayedLimitedReader(input, 3, tt.delay)
+			} else {
+				r = LimitedReader(input, 3)
+			}
+			drv := NewTerminalReader(r, "xterm-256color")
+			drv.SetRepeatCoalesce(tt.window)
+
+			eventc := make(chan Event)
+			go func(t testing.TB) {
+				defer close(eventc)
+				if err := drv.StreamEvents(t.Context(), eventc); err != nil {
+					t.Errorf("error streaming events: %v", err)
+				}
+			}(t)
+
+			var events []Event
+			for ev := range eventc {
+				events = append(events, ev)
+			}
+
+			if !reflect.DeepEqual(tt.want, events) {
+				t.Errorf("unexpected messages, expected:\n    %+v\ngot:\n    %+v", tt.want, events)
+			}
+		})
+	}
+}
+
+func TestReadMaxPasteSize(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int
+		want PasteEvent
+	}{
+		{
+			name: "unlimited",
+			want: PasteEvent{Content: "hello 世界"},
+		},
+		{
+			name: "within limit",
+			max:  12,
+			want: PasteEvent{Content: "hello 世界"},
+		},
+		{
+			name: "truncated",
+			max:  5,
+			want: PasteEvent{Content: "hello", Truncated: true},
+		},
+		{
+			name: "truncated inside a rune",
+			max:  8,
+			want: PasteEvent{Content: "hello ", Truncated: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := strings.NewReader("\x1b[200~hello 世界\x1b[201~")
+			drv := NewTerminalReader(LimitedReader(input, 4), "xterm-256color")
+			drv.SetMaxPasteSize(tt.max)
+
+			eventc := make(chan Event)
+			go func(t testing.TB) {
+				defer close(eventc)
+				if err := drv.StreamEvents(t.Context(), eventc); err != nil {
+					t.Errorf("error streaming events: %v", err)
+				}
+			}(t)
+
+			var events []Event
+			for ev := range eventc {
+				events = append(events, ev)
+			}
+
+			want := []Event{PasteStartEvent{}, tt.want, PasteEndEvent{}}
+			if !reflect.DeepEqual(want, events) {
+				t.Errorf("unexpected messages, expected:\n    %+v\ngot:\n    %+v", want, events)
+			}
+		})
+	}
+}
+
+func TestKeyIsModifierOnly(t *testing.T) {
+	tests := []struct {
+		name string
+		key  Key
+		want bool
+	}{
+		{name: "left ctrl", key: Key{Code: KeyLeftCtrl, Mod: ModCtrl}, want: true},
+		{name: "right shift", key: Key{Code: KeyRightShift}, want: true},
+		{name: "right meta", key: Key{Code: KeyRightMeta}, want: true},
+		{name: "iso level 3 shift", key: Key{Code: KeyIsoLevel3Shift}, want: true},
+		{name: "ctrl+a", key: Key{Code: 'a', Mod: ModCtrl}},
+		{name: "caps lock", key: Key{Code: KeyCapsLock}},
+		{name: "with text", key: Key{Code: KeyLeftAlt, Text: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.IsModifierOnly(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadFilterModifierKeys(t *testing.T) {
+	// Left ctrl press, ctrl+c press, left ctrl release.
+	const input = "\x1b[57442;5u\x1b[99;5u\x1b[57442;1:3u"
+
+	tests := []struct {
+		name   string
+		filter bool
+		want   []Event
+	}{
+		{
+			name: "unfiltered",
+			want: []Event{
+				KeyPressEvent{Code: KeyLeftCtrl, Mod: ModCtrl},
+				KeyPressEvent{Code: 'c', Mod: ModCtrl},
+				KeyReleaseEvent{Code: KeyLeftCtrl},
+			},
+		},
+		{
+			name:   "filtered",
+			filter: true,
+			want: []Event{
+				KeyPressEvent{Code: 'c', Mod: ModCtrl},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drv := NewTerminalReader(strings.NewReader(input), "xterm-256color")
+			drv.SetFilterModifierKeys(tt.filter)
+
+			eventc := make(chan Event)
+			go func(t testing.TB) {
+				defer close(eventc)
+				if err := drv.StreamEvents(t.Context(), eventc); err != nil {
+					t.Errorf("error streaming events: %v", err)
+				}
+			}(t)
+
+			var events []Event
+			for ev := range eventc {
+				events = append(events, ev)
+			}
+
+			if !reflect.DeepEqual(tt.want, events) {
+				t.Errorf("unexpected messages, expected:\n    %#v\ngot:\n    %#v", tt.want, events)
+			}
+		})
+	}
+}
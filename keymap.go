@@ -0,0 +1,98 @@
+package uv
+
+// KeyBinding is a named action bound to a list of keys in a [KeyMap].
+type KeyBinding struct {
+	// Action is the name of the action.
+	Action string
+	// Keys is the list of keys that trigger the action. See
+	// [Key.MatchString] for the supported key strings.
+	Keys []string
+}
+
+// KeyMap maps key presses to named actions. It centralizes the key bindings
+// of an application, and can be used to list them in a help view.
+//
+// Example:
+//
+//	```go
+//	km := NewKeyMap()
+//	km.Bind("quit", "ctrl+c", "q")
+//	km.Bind("up", "up", "k")
+//
+//	switch ev := ev.(type) {
+//	case KeyPressEvent:
+//	    if action, ok := km.Action(ev); ok && action == "quit" {
+//	        return
+//	    }
+//	}
+//	```
+type KeyMap struct {
+	bindings []KeyBinding
+}
+
+// NewKeyMap returns a new empty [KeyMap].
+func NewKeyMap() *KeyMap {
+	return new(KeyMap)
+}
+
+// Bind binds the given keys to the action. Keys are added to the keys already
+// bound to the action, if any.
+func (km *KeyMap) Bind(action string, keys ...string) {
+	if i := km.index(action); i >= 0 {
+		km.bindings[i].Keys = append(km.bindings[i].Keys, keys...)
+		return
+	}
+	km.bindings = append(km.bindings, KeyBinding{
+		Action: action,
+		Keys:   append([]string(nil), keys...),
+	})
+}
+
+// Unbind removes the action and all of its keys from the key map.
+func (km *KeyMap) Unbind(action string) {
+	if i := km.index(action); i >= 0 {
+		km.bindings = append(km.bindings[:i], km.bindings[i+1:]...)
+	}
+}
+
+// Action returns the action bound to the given key press. Keys are matched
+// using [KeyPressEvent.MatchString]. When a key is bound to more than one
+// action, the action that was bound first wins.
+func (km *KeyMap) Action(k KeyPressEvent) (string, bool) {
+	for _, b := range km.bindings {
+		if k.MatchString(b.Keys...) {
+			return b.Action, true
+		}
+	}
+	return "", false
+}
+
+// Keys returns the keys bound to the given action.
+func (km *KeyMap) Keys(action string) []string {
+	if i := km.index(action); i >= 0 {
+		return append([]string(nil), km.bindings[i].Keys...)
+	}
+	return nil
+}
+
+// Bindings returns all the key bindings in the order their actions were first
+// bound.
+func (km *KeyMap) Bindings() []KeyBinding {
+	bindings := make([]KeyBinding, len(km.bindings))
+	for i, b := range km.bindings {
+		bindings[i] = KeyBinding{
+			Action: b.Action,
+			Keys:   append([]string(nil), b.Keys...),
+		}
+	}
+	return bindings
+}
+
+func (km *KeyMap) index(action string) int {
+	for i, b := range km.bindings {
+		if b.Action == action {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,79 @@
+package uv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyMap(t *testing.T) {
+	km := NewKeyMap()
+	km.Bind("quit", "ctrl+c", "q")
+	km.Bind("up", "up", "k")
+	km.Bind("select", "enter", "q") // "q" is already bound to quit
+	km.Bind("up", "ctrl+p")
+
+	tests := []struct {
+		name   string
+		key    KeyPressEvent
+		action string
+		ok     bool
+	}{
+		{
+			name:   "modifier",
+			key:    KeyPressEvent{Code: 'c', Mod: ModCtrl},
+			action: "quit",
+			ok:     true,
+		},
+		{
+			name:   "text",
+			key:    KeyPressEvent{Code: 'k', Text: "k"},
+			action: "up",
+			ok:     true,
+		},
+		{
+			name:   "added key",
+			key:    KeyPressEvent{Code: 'p', Mod: ModCtrl},
+			action: "up",
+			ok:     true,
+		},
+		{
+			name:   "first bound action wins",
+			key:    KeyPressEvent{Code: 'q', Text: "q"},
+			action: "quit",
+			ok:     true,
+		},
+		{
+			name: "unbound",
+			key:  KeyPressEvent{Code: 'x', Text: "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, ok := km.Action(tt.key)
+			if action != tt.action || ok != tt.ok {
+				t.Errorf("expected action %q (%v), got %q (%v)", tt.action, tt.ok, action, ok)
+			}
+			if tt.ok && !tt.key.MatchString(km.Keys(action)...) {
+				t.Errorf("expected key to match the %q keys inline", action)
+			}
+		})
+	}
+
+	want := []KeyBinding{
+		{Action: "quit", Keys: []string{"ctrl+c", "q"}},
+		{Action: "up", Keys: []string{"up", "k", "ctrl+p"}},
+		{Action: "select", Keys: []string{"enter", "q"}},
+	}
+	if got := km.Bindings(); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected bindings %v, got %v", want, got)
+	}
+
+	km.Unbind("quit")
+	if action, ok := km.Action(KeyPressEvent{Code: 'q', Text: "q"}); action != "select" || !ok {
+		t.Errorf("expected select action after unbinding quit, got %q (%v)", action, ok)
+	}
+	if keys := km.Keys("quit"); keys != nil {
+		t.Errorf("expected no keys for unbound action, got %v", keys)
+	}
+}
@@ -17,6 +17,47 @@ var (
 	globalCacheMu sync.Mutex
 )
 
+// SetCacheSize sets the maximum number of results kept in the cache used by
+// [Layout.Split] and [Layout.SplitWithSpacers], and clears it. A size of zero
+// or less disables the cache, so every split is computed from scratch. This is
+// mostly useful in tests and benchmarks.
+//
+// The cache is shared by all layouts and is safe for concurrent use.
+func SetCacheSize(size int) {
+	globalCacheMu.Lock()
+	defer globalCacheMu.Unlock()
+
+	if size <= 0 {
+		globalCache = nil
+		return
+	}
+	globalCache = lru.New[cacheKey, cacheValue](size)
+}
+
+// cacheGet returns the cached value for the given key, and reports whether the
+// cache is enabled and whether the key was found. The cache lock is only held
+// while accessing the cache, so splits are computed concurrently.
+func cacheGet(key cacheKey) (v cacheValue, enabled, ok bool) {
+	globalCacheMu.Lock()
+	defer globalCacheMu.Unlock()
+
+	if globalCache == nil {
+		return v, false, false
+	}
+	v, ok = globalCache.Get(key)
+	return v, true, ok
+}
+
+// cacheAdd adds the given value to the cache, if it's enabled.
+func cacheAdd(key cacheKey, v cacheValue) {
+	globalCacheMu.Lock()
+	defer globalCacheMu.Unlock()
+
+	if globalCache != nil {
+		globalCache.Add(key, v)
+	}
+}
+
 type cacheKey struct {
 	Area            uv.Rectangle
 	Direction       Direction
@@ -3,13 +3,14 @@ package layout
 import (
 	"fmt"
 	"io"
+	"math"
 )
 
 // Constraint describes how a single segment of a [Layout] should be sized.
 //
 // Each constraint type expresses a different kind of sizing rule:
-// fixed ([Len]), proportional ([Percent], [Ratio]), bounded ([Min], [Max]),
-// or greedy ([Fill]). Proportional constraints are evaluated against the
+// fixed ([Len], [AspectRatio]), proportional ([Percent], [Ratio]), bounded
+// ([Min], [Max]), or greedy ([Fill]). Proportional constraints are evaluated against the
 // full area being split rather than the remaining space after fixed
 // constraints have been applied.
 //
@@ -18,7 +19,7 @@ import (
 //
 //   - [Min]
 //   - [Max]
-//   - [Len]
+//   - [Len], [AspectRatio]
 //   - [Percent]
 //   - [Ratio]
 //   - [Fill]
@@ -147,6 +148,33 @@ type (
 	// 	│   13 px   ││         25 px         ││   12 px  │
 	// 	└───────────┘└───────────────────────┘└──────────┘
 	Fill int
+
+	// AspectRatio sizes the segment so that the resulting rectangle keeps a
+	// W:H ratio, measured in cells, with the perpendicular dimension of the
+	// area being split. In a horizontal layout, the segment width is the
+	// area height multiplied by W/H. In a vertical layout, the segment height
+	// is the area width multiplied by H/W. The result is rounded to the
+	// nearest cell.
+	//
+	// Once computed, the segment length carries the same priority as [Len].
+	// When the area is too small to fit every segment, the solver shrinks
+	// lower-priority segments such as [Percent], [Ratio], and [Fill] first,
+	// and only then the aspect ratio segment. [Min] and [Max] bounds always
+	// win over it.
+	//
+	// Note that terminal cells are usually about twice as tall as they are
+	// wide, so a visually square area is closer to AspectRatio{2, 1}.
+	//
+	// # Examples
+	//
+	// 	Horizontal split of a 50x10 area
+	//
+	// 	[AspectRatio{2, 1}, Fill(1)]
+	//
+	// 	┌──────────────────┐┌────────────────────────────┐
+	// 	│       20 px      ││            30 px           │
+	// 	└──────────────────┘└────────────────────────────┘
+	AspectRatio struct{ W, H int }
 )
 
 func (m Min) String() string   { return fmt.Sprintf("Min(%d)", m) }
@@ -172,3 +200,20 @@ func (Ratio) isConstraint()      {}
 func (f Fill) String() string   { return fmt.Sprintf("Fill(%d)", f) }
 func (f Fill) hash(w io.Writer) { fmt.Fprint(w, "fill", f) }
 func (Fill) isConstraint()      {}
+
+func (a AspectRatio) String() string   { return fmt.Sprintf("AspectRatio(%d:%d)", a.W, a.H) }
+func (a AspectRatio) hash(w io.Writer) { fmt.Fprint(w, "aspect", a.W, a.H) }
+func (AspectRatio) isConstraint()      {}
+
+// length returns the segment length for the given perpendicular dimension of
+// the area.
+func (a AspectRatio) length(direction Direction, cross int) int {
+	var f float64
+	switch direction {
+	case DirectionHorizontal:
+		f = float64(a.W) / float64(max(1, a.H))
+	case DirectionVertical:
+		f = float64(a.H) / float64(max(1, a.W))
+	}
+	return max(0, int(math.Round(float64(cross)*f)))
+}
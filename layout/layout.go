@@ -8,7 +8,8 @@
 // # How It Works
 //
 // A [Layout] takes the available area and a list of constraints ([Len], [Ratio],
-// [Percent], [Fill], [Min], [Max]) and produces a set of non-overlapping rectangles.
+// [Percent], [Fill], [Min], [Max], [AspectRatio]) and produces a set of
+// non-overlapping rectangles.
 // The solver tries to honour every constraint; when that is impossible it
 // relaxes lower-priority ones first.
 //
@@ -28,6 +29,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"math"
+	"slices"
 
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/ultraviolet/internal/casso"
@@ -68,7 +70,8 @@ const (
 	// 	└────────┘
 	maxSizeLTE = strong * 100.0
 
-	// lengthSizeEq pins the segment to the exact size requested by a [Len] constraint.
+	// lengthSizeEq pins the segment to the exact size requested by a [Len]
+	// or [AspectRatio] constraint.
 	//
 	// 	┌────────┐
 	// 	│Len(==x)│
@@ -271,21 +274,23 @@ func (l Layout) Split(area uv.Rectangle) Splitted {
 }
 
 func (l Layout) splitCached(area uv.Rectangle) (segments, spacers []uv.Rectangle, err error) {
-	globalCacheMu.Lock()
-	defer globalCacheMu.Unlock()
-
 	key := l.cacheKey(area)
 
-	if v, ok := globalCache.Get(key); ok {
-		return v.Segments, v.Spacers, nil
+	// Cached results are cloned so callers can't modify them.
+	v, enabled, ok := cacheGet(key)
+	if ok {
+		return slices.Clone(v.Segments), slices.Clone(v.Spacers), nil
 	}
 
 	segments, spacers, err = l.split(area)
 	if err != nil {
 		return nil, nil, err
 	}
+	if !enabled {
+		return segments, spacers, nil
+	}
 
-	globalCache.Add(key, cacheValue{Segments: segments, Spacers: spacers})
+	cacheAdd(key, cacheValue{Segments: slices.Clone(segments), Spacers: slices.Clone(spacers)})
 
 	return segments, spacers, nil
 }
@@ -296,15 +301,18 @@ func (l Layout) split(area uv.Rectangle) (segments, spacers []uv.Rectangle, err
 	innerArea := l.Padding.apply(area)
 
 	var areaStart, areaEnd float64
+	var cross int
 
 	switch l.Direction {
 	case DirectionHorizontal:
 		areaStart = float64(innerArea.Min.X) * floatPrecisionMultiplier
 		areaEnd = float64(innerArea.Max.X) * floatPrecisionMultiplier
+		cross = innerArea.Dy()
 
 	case DirectionVertical:
 		areaStart = float64(innerArea.Min.Y) * floatPrecisionMultiplier
 		areaEnd = float64(innerArea.Max.Y) * floatPrecisionMultiplier
+		cross = innerArea.Dx()
 	}
 
 	// 	<───────────────────────────────────area_size──────────────────────────────────>
@@ -357,7 +365,7 @@ func (l Layout) split(area uv.Rectangle) (segments, spacers []uv.Rectangle, err
 		return nil, nil, fmt.Errorf("configure flex constraints: %w", err)
 	}
 
-	if err := configureConstraints(s, areaEl, segmentElements, l.Constraints, l.Flex); err != nil {
+	if err := configureConstraints(s, areaEl, segmentElements, l.Constraints, l.Flex, l.Direction, cross); err != nil {
 		return nil, nil, fmt.Errorf("configure constraints: %w", err)
 	}
 
@@ -529,6 +537,8 @@ func configureConstraints(
 	segments []element,
 	constraints []Constraint,
 	flex Flex,
+	direction Direction,
+	cross int,
 ) error {
 	for i := 0; i < min(len(constraints), len(segments)); i++ {
 		constraint := constraints[i]
@@ -570,6 +580,13 @@ func configureConstraints(
 				return fmt.Errorf("add has int size constraint: %w", err)
 			}
 
+		case AspectRatio:
+			length := constraint.length(direction, cross)
+
+			if _, err := s.Add(lengthSizeEq, segment.sizeEqConst(length)); err != nil {
+				return fmt.Errorf("add has aspect ratio size constraint: %w", err)
+			}
+
 		case Percent:
 			f := float64(constraint) / 100
 
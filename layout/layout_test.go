@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	uv "github.com/charmbracelet/ultraviolet"
@@ -34,6 +35,62 @@ func BenchmarkLayout_Split(b *testing.B) {
 	})
 }
 
+func TestSplitCache(t *testing.T) {
+	layout := Horizontal(Len(2), Fill(1), Percent(30)).WithSpacing(1)
+	area := uv.Rect(0, 0, 40, 1)
+
+	want, _, err := layout.split(area)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("cached results are copies", func(t *testing.T) {
+		got := layout.Split(area)
+		got[0] = uv.Rectangle{}
+		if got := layout.Split(area); !reflect.DeepEqual(Splitted(want), got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("cache keyed by layout", func(t *testing.T) {
+		got := layout.Split(area)
+		other := layout.WithSpacing(0).Split(area)
+		if reflect.DeepEqual(got, other) {
+			t.Errorf("expected different results for different spacing, got %v", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		SetCacheSize(0)
+		defer SetCacheSize(globalCacheSize)
+
+		if got := layout.Split(area); !reflect.DeepEqual(Splitted(want), got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Go(func() {
+				area := uv.Rect(0, 0, 40+i%2, 1)
+				want, _, err := layout.split(area)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for range 100 {
+					if got := layout.Split(area); !reflect.DeepEqual(Splitted(want), got) {
+						t.Errorf("expected %v, got %v", want, got)
+						return
+					}
+				}
+			})
+		}
+		wg.Wait()
+	})
+}
+
 func TestPriorityIsValid(t *testing.T) {
 	t.Parallel()
 
@@ -967,6 +1024,111 @@ func TestFlexConstraint(t *testing.T) {
 	}
 }
 
+func TestAspectRatio(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		constraints []Constraint
+		direction   Direction
+		area        uv.Rectangle
+		want        [][]int
+	}{
+		{
+			name: "horizontal",
+			constraints: []Constraint{
+				AspectRatio{2, 1},
+				Fill(1),
+			},
+			direction: DirectionHorizontal,
+			area:      uv.Rect(0, 0, 100, 10),
+			want:      [][]int{{0, 20}, {20, 100}},
+		},
+		{
+			name: "horizontal rounded",
+			constraints: []Constraint{
+				AspectRatio{16, 9},
+				Fill(1),
+			},
+			direction: DirectionHorizontal,
+			area:      uv.Rect(0, 0, 100, 10),
+			want:      [][]int{{0, 18}, {18, 100}},
+		},
+		{
+			name: "vertical",
+			constraints: []Constraint{
+				Fill(1),
+				AspectRatio{2, 1},
+			},
+			direction: DirectionVertical,
+			area:      uv.Rect(0, 0, 40, 100),
+			want:      [][]int{{0, 80}, {80, 100}},
+		},
+		{
+			name: "zero cross dimension",
+			constraints: []Constraint{
+				AspectRatio{2, 1},
+				Fill(1),
+			},
+			direction: DirectionHorizontal,
+			area:      uv.Rect(0, 0, 100, 0),
+			want:      [][]int{{0, 0}, {0, 100}},
+		},
+		{
+			name: "overflow shrinks fill first",
+			constraints: []Constraint{
+				AspectRatio{2, 1},
+				Fill(1),
+			},
+			direction: DirectionHorizontal,
+			area:      uv.Rect(0, 0, 30, 20),
+			want:      [][]int{{0, 30}, {30, 30}},
+		},
+		{
+			name: "overflow keeps percent after aspect ratio",
+			constraints: []Constraint{
+				AspectRatio{2, 1},
+				Percent(50),
+			},
+			direction: DirectionHorizontal,
+			area:      uv.Rect(0, 0, 30, 10),
+			want:      [][]int{{0, 20}, {20, 30}},
+		},
+		{
+			name: "min wins over aspect ratio",
+			constraints: []Constraint{
+				AspectRatio{2, 1},
+				Min(20),
+			},
+			direction: DirectionHorizontal,
+			area:      uv.Rect(0, 0, 30, 20),
+			want:      [][]int{{0, 10}, {10, 30}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rects := New(tc.direction, tc.constraints...).WithFlex(FlexStart).Split(tc.area)
+
+			ranges := make([][]int, 0, len(rects))
+
+			for _, r := range rects {
+				if tc.direction == DirectionHorizontal {
+					ranges = append(ranges, []int{r.Min.X, r.Max.X})
+				} else {
+					ranges = append(ranges, []int{r.Min.Y, r.Max.Y})
+				}
+			}
+
+			if !reflect.DeepEqual(tc.want, ranges) {
+				t.Fatalf("not equal: want %#+v, got %#+v", tc.want, ranges)
+			}
+		})
+	}
+}
+
 func TestFlexSpacing(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,206 @@
+package layout
+
+import (
+	"errors"
+	"math"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+var (
+	// ErrPaneNotFound is returned when a [PaneID] doesn't belong to a
+	// [PaneTree].
+	ErrPaneNotFound = errors.New("pane not found")
+
+	// ErrLastPane is returned when trying to remove the only pane of a
+	// [PaneTree].
+	ErrLastPane = errors.New("cannot remove the last pane")
+)
+
+// PaneID identifies a single pane in a [PaneTree].
+type PaneID int
+
+// PaneTree is a binary tree of panes that can be split recursively, like the
+// windows of a terminal multiplexer.
+//
+// Each split divides a pane into two along a direction. The ratio of a split
+// is the fraction of the area given to the first pane, the left or top one,
+// and it can be changed at any time, for example, while dragging the divider
+// between the two panes.
+type PaneTree struct {
+	root   *paneNode
+	nextID PaneID
+}
+
+// paneNode is either a leaf holding a pane, or a split holding two children.
+type paneNode struct {
+	id     PaneID
+	parent *paneNode
+
+	// split fields, first and second are nil for leaves.
+	direction     Direction
+	ratio         float64
+	first, second *paneNode
+}
+
+func (n *paneNode) isLeaf() bool {
+	return n.first == nil
+}
+
+// NewPaneTree returns a new [PaneTree] with a single pane and the ID of that
+// pane.
+func NewPaneTree() (*PaneTree, PaneID) {
+	t := new(PaneTree)
+	t.root = &paneNode{id: t.newID()}
+	return t, t.root.id
+}
+
+func (t *PaneTree) newID() PaneID {
+	t.nextID++
+	return t.nextID
+}
+
+// SplitH splits the given pane horizontally into a left and a right pane. The
+// left pane keeps the ID of the original pane and gets the given ratio of the
+// width. It returns the ID of the new right pane.
+func (t *PaneTree) SplitH(id PaneID, ratio float64) (PaneID, error) {
+	return t.split(id, DirectionHorizontal, ratio)
+}
+
+// SplitV splits the given pane vertically into a top and a bottom pane. The
+// top pane keeps the ID of the original pane and gets the given ratio of the
+// height. It returns the ID of the new bottom pane.
+func (t *PaneTree) SplitV(id PaneID, ratio float64) (PaneID, error) {
+	return t.split(id, DirectionVertical, ratio)
+}
+
+func (t *PaneTree) split(id PaneID, direction Direction, ratio float64) (PaneID, error) {
+	n := t.find(t.root, id)
+	if n == nil {
+		return 0, ErrPaneNotFound
+	}
+
+	// The leaf becomes a split node holding the original pane and the new
+	// one.
+	first := &paneNode{id: n.id, parent: n}
+	second := &paneNode{id: t.newID(), parent: n}
+	n.id = 0
+	n.direction = direction
+	n.ratio = clampRatio(ratio)
+	n.first, n.second = first, second
+
+	return second.id, nil
+}
+
+// Remove removes the given pane from the tree. Its sibling takes over the
+// space of the split they shared.
+func (t *PaneTree) Remove(id PaneID) error {
+	n := t.find(t.root, id)
+	if n == nil {
+		return ErrPaneNotFound
+	}
+	if n.parent == nil {
+		return ErrLastPane
+	}
+
+	// Replace the parent split with the sibling.
+	parent := n.parent
+	sibling := parent.first
+	if sibling == n {
+		sibling = parent.second
+	}
+	*parent = paneNode{
+		id:        sibling.id,
+		parent:    parent.parent,
+		direction: sibling.direction,
+		ratio:     sibling.ratio,
+		first:     sibling.first,
+		second:    sibling.second,
+	}
+	if !parent.isLeaf() {
+		parent.first.parent = parent
+		parent.second.parent = parent
+	}
+
+	return nil
+}
+
+// SetRatio changes the ratio of the split that contains the given pane. The
+// ratio is the fraction of the area given to the first pane of the split, and
+// it's clamped between 0 and 1.
+//
+// It returns [ErrPaneNotFound] if the pane doesn't exist or isn't part of a
+// split.
+func (t *PaneTree) SetRatio(id PaneID, ratio float64) error {
+	n := t.find(t.root, id)
+	if n == nil || n.parent == nil {
+		return ErrPaneNotFound
+	}
+	n.parent.ratio = clampRatio(ratio)
+	return nil
+}
+
+// Panes returns the IDs of all the panes in the tree, from left to right and
+// top to bottom.
+func (t *PaneTree) Panes() []PaneID {
+	var ids []PaneID
+	var walk func(n *paneNode)
+	walk = func(n *paneNode) {
+		if n.isLeaf() {
+			ids = append(ids, n.id)
+			return
+		}
+		walk(n.first)
+		walk(n.second)
+	}
+	walk(t.root)
+	return ids
+}
+
+// Layout computes the area of each pane within the given area.
+func (t *PaneTree) Layout(area uv.Rectangle) map[PaneID]uv.Rectangle {
+	areas := make(map[PaneID]uv.Rectangle)
+	var walk func(n *paneNode, area uv.Rectangle)
+	walk = func(n *paneNode, area uv.Rectangle) {
+		if n.isLeaf() {
+			areas[n.id] = area
+			return
+		}
+
+		size := area.Dy()
+		if n.direction == DirectionHorizontal {
+			size = area.Dx()
+		}
+
+		var first, second uv.Rectangle
+		New(n.direction, Len(int(math.Round(float64(size)*n.ratio))), Fill(1)).
+			Split(area).
+			Assign(&first, &second)
+
+		walk(n.first, first)
+		walk(n.second, second)
+	}
+	walk(t.root, area)
+	return areas
+}
+
+// find returns the leaf node of the given pane.
+func (t *PaneTree) find(n *paneNode, id PaneID) *paneNode {
+	if n.isLeaf() {
+		if n.id == id {
+			return n
+		}
+		return nil
+	}
+	if found := t.find(n.first, id); found != nil {
+		return found
+	}
+	return t.find(n.second, id)
+}
+
+func clampRatio(ratio float64) float64 {
+	if math.IsNaN(ratio) {
+		return 0.5 //nolint:mnd
+	}
+	return min(max(ratio, 0), 1)
+}
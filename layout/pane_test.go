@@ -0,0 +1,120 @@
+package layout
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+func TestPaneTree(t *testing.T) {
+	area := uv.Rect(0, 0, 80, 24)
+
+	tree, a := NewPaneTree()
+	if got := tree.Layout(area); !reflect.DeepEqual(map[PaneID]uv.Rectangle{a: area}, got) {
+		t.Fatalf("expected a single pane covering the area, got %v", got)
+	}
+
+	b, err := tree.SplitH(a, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := tree.SplitV(b, 0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[PaneID]uv.Rectangle{
+		a: uv.Rect(0, 0, 40, 24),
+		b: uv.Rect(40, 0, 40, 6),
+		c: uv.Rect(40, 6, 40, 18),
+	}
+	if got := tree.Layout(area); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := tree.Panes(); !reflect.DeepEqual([]PaneID{a, b, c}, got) {
+		t.Errorf("expected panes %v, got %v", []PaneID{a, b, c}, got)
+	}
+
+	// Dragging the vertical divider.
+	if err := tree.SetRatio(a, 0.25); err != nil {
+		t.Fatal(err)
+	}
+	want = map[PaneID]uv.Rectangle{
+		a: uv.Rect(0, 0, 20, 24),
+		b: uv.Rect(20, 0, 60, 6),
+		c: uv.Rect(20, 6, 60, 18),
+	}
+	if got := tree.Layout(area); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// Removing a pane gives its space to its sibling.
+	if err := tree.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	want = map[PaneID]uv.Rectangle{
+		a: uv.Rect(0, 0, 20, 24),
+		c: uv.Rect(20, 0, 60, 24),
+	}
+	if got := tree.Layout(area); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if err := tree.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.Layout(area); !reflect.DeepEqual(map[PaneID]uv.Rectangle{c: area}, got) {
+		t.Errorf("expected pane %d covering the area, got %v", c, got)
+	}
+}
+
+func TestPaneTreeRemoveSplitSibling(t *testing.T) {
+	area := uv.Rect(0, 0, 10, 10)
+
+	tree, a := NewPaneTree()
+	b, _ := tree.SplitV(a, 0.5)
+	c, _ := tree.SplitH(b, 0.5)
+
+	// Removing a leaves the b|c split in place of the root.
+	if err := tree.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	want := map[PaneID]uv.Rectangle{
+		b: uv.Rect(0, 0, 5, 10),
+		c: uv.Rect(5, 0, 5, 10),
+	}
+	if got := tree.Layout(area); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// The moved split is still adjustable.
+	if err := tree.SetRatio(c, 0.2); err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.Layout(area)[b]; got != uv.Rect(0, 0, 2, 10) {
+		t.Errorf("expected pane %d to be %v, got %v", b, uv.Rect(0, 0, 2, 10), got)
+	}
+}
+
+func TestPaneTreeErrors(t *testing.T) {
+	tree, a := NewPaneTree()
+
+	if err := tree.Remove(a); !errors.Is(err, ErrLastPane) {
+		t.Errorf("expected %v, got %v", ErrLastPane, err)
+	}
+	if err := tree.SetRatio(a, 0.5); !errors.Is(err, ErrPaneNotFound) {
+		t.Errorf("expected %v, got %v", ErrPaneNotFound, err)
+	}
+	if _, err := tree.SplitH(a+100, 0.5); !errors.Is(err, ErrPaneNotFound) {
+		t.Errorf("expected %v, got %v", ErrPaneNotFound, err)
+	}
+
+	// Ratios are clamped.
+	b, _ := tree.SplitH(a, 2)
+	area := uv.Rect(0, 0, 10, 1)
+	if got := tree.Layout(area); got[a] != area || !got[b].Empty() {
+		t.Errorf("expected pane %d to take the whole area, got %v", a, got)
+	}
+}
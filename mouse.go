@@ -95,6 +95,13 @@ type Mouse struct {
 	X, Y   int
 	Button MouseButton
 	Mod    KeyMod
+
+	// Count is the number of consecutive clicks, 1 for a single click, 2 for
+	// a double click, and 3 for a triple click. It's only set on
+	// [MouseClickEvent], and is zero on other mouse events. See
+	// [TerminalReader.SetDoubleClickInterval] and
+	// [Options.DoubleClickInterval].
+	Count int
 }
 
 // String returns a string representation of the mouse message.
@@ -0,0 +1,383 @@
+package uv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultProbeTimeout is the default duration [Terminal.Probe] waits for the
+// terminal to respond when the given context has no deadline.
+const DefaultProbeTimeout = time.Second
+
+// ErrTerminalStarted is returned when an operation that needs exclusive access
+// to the terminal input is called after [Terminal.Start].
+var ErrTerminalStarted = errors.New("terminal already started")
+
+// Feature represents a terminal feature that can be detected using
+// [Terminal.Probe].
+type Feature int
+
+// Terminal features.
+const (
+	// FeatureSixel indicates that the terminal supports Sixel graphics. This
+	// is detected from the primary device attributes (DA1).
+	FeatureSixel Feature = iota + 1
+	// FeatureKittyKeyboard indicates that the terminal supports the Kitty
+	// keyboard protocol. This is detected from the Kitty keyboard flags
+	// report.
+	FeatureKittyKeyboard
+	// FeatureSynchronizedOutput indicates that the terminal supports
+	// synchronized output mode 2026. This is detected from the mode report
+	// (DECRPM).
+	FeatureSynchronizedOutput
+	// FeatureUnicodeCore indicates that the terminal supports the Unicode
+	// core mode 2027 for grapheme width calculation. This is detected from
+	// the mode report (DECRPM).
+	FeatureUnicodeCore
+	// FeatureInBandResize indicates that the terminal supports in-band resize
+	// notifications mode 2048. This is detected from the mode report
+	// (DECRPM).
+	FeatureInBandResize
+	// FeatureUnicode indicates that the terminal is likely to render Unicode
+	// box drawing characters properly. Legacy terminals that only answer the
+	// primary device attributes query are assumed to be limited to line
+	// drawing with ASCII characters.
+	FeatureUnicode
+	// FeatureKittyGraphics indicates that the terminal supports the Kitty
+	// graphics protocol. This is detected from the response to a Kitty
+	// graphics query.
+	FeatureKittyGraphics
+)
+
+// ImageProtocol represents a protocol used to display images in the terminal.
+type ImageProtocol int
+
+// Image protocols, from the least to the most capable.
+const (
+	// ImageProtocolBlocks draws images using block characters. It works on
+	// any terminal that supports colors.
+	ImageProtocolBlocks ImageProtocol = iota
+	// ImageProtocolSixel draws images using Sixel graphics.
+	ImageProtocolSixel
+	// ImageProtocolITerm2 draws images using the iTerm2 inline images
+	// protocol.
+	ImageProtocolITerm2
+	// ImageProtocolKitty draws images using the Kitty graphics protocol.
+	ImageProtocolKitty
+)
+
+// String returns the name of the image protocol.
+func (p ImageProtocol) String() string {
+	switch p {
+	case ImageProtocolBlocks:
+		return "blocks"
+	case ImageProtocolSixel:
+		return "sixel"
+	case ImageProtocolITerm2:
+		return "iterm2"
+	case ImageProtocolKitty:
+		return "kitty"
+	}
+	return fmt.Sprintf("ImageProtocol(%d)", int(p))
+}
+
+// Capabilities is a summary of the terminal responses gathered by
+// [Terminal.Probe].
+type Capabilities struct {
+	// PrimaryAttributes is the list of primary device attributes (DA1)
+	// reported by the terminal.
+	PrimaryAttributes []int
+	// SecondaryAttributes is the list of secondary device attributes (DA2)
+	// reported by the terminal.
+	SecondaryAttributes []int
+	// Version is the terminal name and version reported by XTVERSION.
+	Version string
+	// KeyboardFlags is the Kitty keyboard flags reported by the terminal. It
+	// is -1 if the terminal didn't respond to the query.
+	KeyboardFlags int
+	// Modes is the list of mode reports (DECRPM) sent by the terminal.
+	Modes ansi.Modes
+	// KittyGraphics reports whether the terminal answered the Kitty graphics
+	// query.
+	KittyGraphics bool
+}
+
+// Supports reports whether the terminal supports the given feature according
+// to the probed responses.
+func (c *Capabilities) Supports(f Feature) bool {
+	switch f {
+	case FeatureSixel:
+		return slices.Contains(c.PrimaryAttributes, 4) //nolint:mnd
+	case FeatureKittyKeyboard:
+		return c.KeyboardFlags >= 0
+	case FeatureSynchronizedOutput:
+		return c.recognized(ansi.ModeSynchronizedOutput)
+	case FeatureUnicodeCore:
+		return c.recognized(ansi.ModeUnicodeCore)
+	case FeatureInBandResize:
+		return c.recognized(ansi.ModeInBandResize)
+	case FeatureUnicode:
+		return c.Version != "" ||
+			c.KeyboardFlags >= 0 ||
+			c.recognized(ansi.ModeSynchronizedOutput) ||
+			c.recognized(ansi.ModeUnicodeCore)
+	case FeatureKittyGraphics:
+		return c.KittyGraphics
+	}
+	return false
+}
+
+// Border returns the [NormalBorder] if the terminal supports
+// [FeatureUnicode], otherwise, it falls back to the [ASCIIBorder].
+func (c *Capabilities) Border() Border {
+	if c.Supports(FeatureUnicode) {
+		return NormalBorder()
+	}
+	return ASCIIBorder()
+}
+
+func (c *Capabilities) recognized(m ansi.Mode) bool {
+	v, ok := c.Modes[m]
+	return ok && !v.IsNotRecognized()
+}
+
+// probeKittyGraphicsID is the image ID used to query Kitty graphics support.
+const probeKittyGraphicsID = 31
+
+// probeModes are the modes queried by [Terminal.Probe].
+var probeModes = []ansi.Mode{
+	ansi.ModeSynchronizedOutput,
+	ansi.ModeUnicodeCore,
+	ansi.ModeInBandResize,
+}
+
+// Probe queries the terminal for its capabilities and waits for the
+// responses. The results are available through [Terminal.Capabilities] and
+// [Terminal.Supports].
+//
+// The following queries are sent:
+//
+//   - XTVERSION for the terminal name and version
+//   - DA2 for the secondary device attributes
+//   - Kitty keyboard flags for [FeatureKittyKeyboard]
+//   - Kitty graphics query for [FeatureKittyGraphics]
+//   - DECRQM for [FeatureSynchronizedOutput], [FeatureUnicodeCore], and
+//     [FeatureInBandResize]
+//   - DA1 for the primary device attributes and [FeatureSixel]
+//
+// Since virtually every terminal answers DA1, it is sent last and its
+// response marks the end of the probe. Queries a terminal doesn't understand
+// are simply left unanswered. If no DA1 response arrives before the context
+// is done, or within [DefaultProbeTimeout] when the context has no deadline,
+// Probe keeps whatever responses were received and returns an error.
+//
+// Probe reads from the terminal input directly and must be called before
+// [Terminal.Start], otherwise, it returns [ErrTerminalStarted].
+func (t *Terminal) Probe(ctx context.Context) error {
+	if t.started() {
+		return ErrTerminalStarted
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultProbeTimeout)
+		defer cancel()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(ansi.RequestNameVersion)
+	sb.WriteString(ansi.RequestSecondaryDeviceAttributes)
+	sb.WriteString(ansi.RequestKittyKeyboard)
+	sb.WriteString(ansi.KittyGraphics([]byte("AAAA"),
+		"i="+strconv.Itoa(probeKittyGraphicsID), "s=1", "v=1", "a=q", "t=d", "f=24"))
+	for _, m := range probeModes {
+		sb.WriteString(ansi.RequestMode(m))
+	}
+	sb.WriteString(ansi.RequestPrimaryDeviceAttributes)
+
+	caps := Capabilities{KeyboardFlags: -1, Modes: ansi.Modes{}}
+	defer func() {
+		t.mu.Lock()
+		t.caps = &caps
+		for m, v := range caps.Modes {
+			t.modes[m] = v
+		}
+		t.mu.Unlock()
+	}()
+
+	if err := t.query(ctx, sb.String(), func(ev Event) bool {
+		switch ev := ev.(type) {
+		case TerminalVersionEvent:
+			caps.Version = ev.Name
+		case SecondaryDeviceAttributesEvent:
+			caps.SecondaryAttributes = ev
+		case KeyboardEnhancementsEvent:
+			caps.KeyboardFlags = ev.Flags
+		case ModeReportEvent:
+			caps.Modes[ev.Mode] = ev.Value
+		case KittyGraphicsEvent:
+			if ev.Options.ID == probeKittyGraphicsID {
+				caps.KittyGraphics = true
+			}
+		case PrimaryDeviceAttributesEvent:
+			caps.PrimaryAttributes = ev
+			return true
+		}
+		return false
+	}); err != nil {
+		return fmt.Errorf("probing terminal: %w", err)
+	}
+	return nil
+}
+
+// query writes the given queries to the terminal and reads its input directly,
+// passing every decoded event to handle until it returns true or the context
+// is done. It must be called while the terminal isn't started.
+func (t *Terminal) query(ctx context.Context, queries string, handle func(Event) bool) error {
+	if _, err := t.con.MakeRaw(); err != nil {
+		return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+	}
+	defer t.con.Restore() //nolint:errcheck
+
+	pr, err := newPollReader(t.con.Reader())
+	if err != nil {
+		return fmt.Errorf("failed to create poll reader: %w", err)
+	}
+
+	if _, err := t.con.Write([]byte(queries)); err != nil {
+		_ = pr.Close()
+		return fmt.Errorf("failed to write terminal queries: %w", err)
+	}
+
+	// input loop
+	bufc := make(chan []byte)
+	errc := make(chan error, 1)
+	donec := make(chan struct{})
+	go func() {
+		for {
+			buf := make([]byte, t.opts.BufferSize)
+			n, err := pr.Read(buf)
+			if err != nil {
+				errc <- fmt.Errorf("reading terminal input: %w", err)
+				return
+			}
+			select {
+			case bufc <- buf[:n]:
+			case <-donec:
+				return
+			}
+		}
+	}()
+	defer func() {
+		// Like [Terminal.Stop], we don't wait for the input loop to return
+		// since readers that can't be canceled would block until the next
+		// input arrives.
+		close(donec)
+		pr.Cancel()
+		_ = pr.Close()
+	}()
+
+	evs := newEventScanner()
+	evs.Legacy = t.opts.LegacyKeyEncoding
+	var buf []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck
+		case err := <-errc:
+			return err
+		case data := <-bufc:
+			buf = append(buf, data...)
+		}
+
+		n, events := evs.scanEvents(buf, false)
+		buf = buf[n:]
+		for _, ev := range events {
+			if handle(ev) {
+				return nil
+			}
+		}
+	}
+}
+
+// Capabilities returns the terminal capabilities gathered by the last call to
+// [Terminal.Probe]. It returns nil if the terminal hasn't been probed yet.
+func (t *Terminal) Capabilities() *Capabilities {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.caps
+}
+
+// Supports reports whether the terminal supports the given feature. It always
+// returns false if the terminal hasn't been probed using [Terminal.Probe].
+func (t *Terminal) Supports(f Feature) bool {
+	caps := t.Capabilities()
+	return caps != nil && caps.Supports(f)
+}
+
+// itermPrograms are the TERM_PROGRAM values of terminals that support iTerm2
+// inline images.
+var itermPrograms = []string{
+	"iTerm",
+	"WezTerm",
+	"mintty",
+	"vscode",
+	"Tabby",
+	"Hyper",
+	"rio",
+}
+
+// DetectImageProtocol returns the most capable image protocol supported by the
+// terminal, in order of preference: Kitty graphics, iTerm2 inline images,
+// Sixel, and blocks.
+//
+// It probes the terminal using [Terminal.Probe] if it hasn't been probed yet,
+// and uses the terminal name and version, along with the TERM_PROGRAM and
+// LC_TERMINAL environment variables, to detect iTerm2 inline images support.
+// LC_TERMINAL is set by iTerm2 and, unlike TERM_PROGRAM, usually makes it
+// through tmux and SSH. If probing
+// fails, the protocol is detected from whatever responses were received, and
+// the error is returned along with it. Like [Terminal.Probe], it must be called
+// before [Terminal.Start] unless the terminal has already been probed.
+func DetectImageProtocol(t *Terminal) (ImageProtocol, error) {
+	var err error
+	caps := t.Capabilities()
+	if caps == nil {
+		err = t.Probe(context.Background())
+		if caps = t.Capabilities(); caps == nil {
+			caps = &Capabilities{KeyboardFlags: -1}
+		}
+	}
+
+	termProg := t.con.Getenv("TERM_PROGRAM")
+	iterm := func(name string) bool {
+		return strings.Contains(name, "iTerm") || strings.Contains(name, "WezTerm")
+	}
+	itermProgram := slices.ContainsFunc(itermPrograms, func(p string) bool {
+		return strings.Contains(termProg, p)
+	})
+
+	// WezTerm answers the Kitty graphics query but doesn't support Unicode
+	// placeholders, so its iTerm2 support is preferred.
+	wezterm := strings.Contains(caps.Version, "WezTerm") ||
+		strings.Contains(termProg, "WezTerm") ||
+		strings.Contains(t.con.Getenv("TERM"), "wezterm")
+
+	switch {
+	case caps.Supports(FeatureKittyGraphics) && !wezterm:
+		return ImageProtocolKitty, err
+	case iterm(caps.Version) || itermProgram ||
+		strings.Contains(t.con.Getenv("LC_TERMINAL"), "iTerm"):
+		return ImageProtocolITerm2, err
+	case caps.Supports(FeatureSixel):
+		return ImageProtocolSixel, err
+	}
+	return ImageProtocolBlocks, err
+}
@@ -0,0 +1,192 @@
+package uv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTerminalProbe(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses string
+		supports  []Feature
+		missing   []Feature
+		border    Border
+		version   string
+	}{
+		{
+			name: "modern terminal",
+			responses: "\x1bP>|kitty(0.30.0)\x1b\\" +
+				"\x1b[>1;4000;19c" +
+				"\x1b[?0u" +
+				"\x1b[?2026;2$y" +
+				"\x1b[?2027;0$y" +
+				"\x1b[?2048;2$y" +
+				"\x1b[?62;4;22c",
+			supports: []Feature{FeatureSixel, FeatureKittyKeyboard, FeatureSynchronizedOutput, FeatureInBandResize, FeatureUnicode},
+			missing:  []Feature{FeatureUnicodeCore, FeatureKittyGraphics},
+			border:   NormalBorder(),
+			version:  "kitty(0.30.0)",
+		},
+		{
+			name:      "legacy terminal",
+			responses: "\x1b[?1;2c",
+			missing:   []Feature{FeatureSixel, FeatureKittyKeyboard, FeatureSynchronizedOutput, FeatureUnicodeCore, FeatureInBandResize, FeatureUnicode},
+			border:    ASCIIBorder(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			con := &testConsole{in: strings.NewReader(tt.responses)}
+			term := NewTerminal(con, nil)
+			if term.Supports(FeatureUnicode) {
+				t.Fatal("expected no features before probing")
+			}
+			if err := term.Probe(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.HasSuffix(con.String(), ansi.RequestPrimaryDeviceAttributes) {
+				t.Errorf("expected DA1 to be the last query, got %q", con.String())
+			}
+			caps := term.Capabilities()
+			if caps.Version != tt.version {
+				t.Errorf("expected version %q, got %q", tt.version, caps.Version)
+			}
+			for _, f := range tt.supports {
+				if !term.Supports(f) {
+					t.Errorf("expected feature %d to be supported", f)
+				}
+			}
+			for _, f := range tt.missing {
+				if term.Supports(f) {
+					t.Errorf("expected feature %d to be unsupported", f)
+				}
+			}
+			if caps.Border() != tt.border {
+				t.Errorf("expected border %+v, got %+v", tt.border, caps.Border())
+			}
+		})
+	}
+}
+
+func TestTerminalProbeTimeout(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close() //nolint:errcheck
+
+	con := &testConsole{in: r}
+	term := NewTerminal(con, &Options{EventTimeout: 5 * time.Millisecond})
+	go func() {
+		// Answer only some of the queries.
+		_, _ = io.WriteString(w, "\x1b[?2026;1$y")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := term.Probe(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded error, got %v", err)
+	}
+	if !term.Supports(FeatureSynchronizedOutput) {
+		t.Error("expected responses received before the timeout to be kept")
+	}
+}
+
+func TestDetectImageProtocol(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       []string
+		responses string
+		expected  ImageProtocol
+	}{
+		{
+			name:      "kitty",
+			responses: "\x1bP>|kitty(0.30.0)\x1b\\" + "\x1b_Gi=31;OK\x1b\\" + "\x1b[?62;4;22c",
+			expected:  ImageProtocolKitty,
+		},
+		{
+			name:      "wezterm",
+			responses: "\x1bP>|WezTerm 20240203\x1b\\" + "\x1b_Gi=31;OK\x1b\\" + "\x1b[?62;4;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "iterm2 program",
+			env:       []string{"TERM_PROGRAM=iTerm.app"},
+			responses: "\x1b[?62;4;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "mintty program",
+			env:       []string{"TERM_PROGRAM=mintty"},
+			responses: "\x1b[?62;4;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "vscode program",
+			env:       []string{"TERM_PROGRAM=vscode"},
+			responses: "\x1b[?62;4;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "tabby program",
+			env:       []string{"TERM_PROGRAM=Tabby"},
+			responses: "\x1b[?62;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "hyper program",
+			env:       []string{"TERM_PROGRAM=Hyper"},
+			responses: "\x1b[?62;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "rio program",
+			env:       []string{"TERM_PROGRAM=rio"},
+			responses: "\x1b[?62;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "iterm2 over tmux",
+			env:       []string{"TERM_PROGRAM=tmux", "LC_TERMINAL=iTerm2"},
+			responses: "\x1b[?62;4;22c",
+			expected:  ImageProtocolITerm2,
+		},
+		{
+			name:      "other program",
+			env:       []string{"TERM_PROGRAM=Apple_Terminal"},
+			responses: "\x1b[?62;4;22c",
+			expected:  ImageProtocolSixel,
+		},
+		{
+			name:      "sixel",
+			responses: "\x1b[?62;4;22c",
+			expected:  ImageProtocolSixel,
+		},
+		{
+			name:      "blocks",
+			responses: "\x1b[?62;22c",
+			expected:  ImageProtocolBlocks,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			con := &testConsole{in: strings.NewReader(tt.responses), env: tt.env}
+			term := NewTerminal(con, nil)
+			p, err := DetectImageProtocol(term)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p != tt.expected {
+				t.Errorf("expected protocol %s, got %s", tt.expected, p)
+			}
+		})
+	}
+}
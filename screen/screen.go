@@ -73,6 +73,46 @@ func FillArea(scr uv.Screen, cell *uv.Cell, area uv.Rectangle) {
 	}
 }
 
+// FillStyle fills the given area of the screen with spaces of the given
+// style. This is the common way to draw a background. See [FillArea].
+func FillStyle(scr uv.Screen, area uv.Rectangle, style uv.Style) {
+	cell := uv.EmptyCell
+	cell.Style = style
+	FillArea(scr, &cell, area)
+}
+
+// FillRune fills the given area of the screen with the rune r of the given
+// style. The width of the rune is measured using the screen width method, and
+// wide runes are repeated every rune width columns. Zero-width runes, such as
+// control characters and combining marks, can't fill a cell and leave the
+// screen untouched. See [FillArea].
+func FillRune(scr uv.Screen, area uv.Rectangle, r rune, style uv.Style) {
+	cell := uv.NewCell(scr.WidthMethod(), string(r))
+	if cell.Width == 0 {
+		return
+	}
+	cell.Style = style
+	FillArea(scr, cell, area)
+}
+
+// InheritStyle applies the given style to the cells in the given area that
+// don't define their own, see [uv.Style.Inherit]. This is the common way to
+// give a base style to content drawn with its own styles, such as a
+// [uv.StyledString].
+func InheritStyle(scr uv.Screen, area uv.Rectangle, style uv.Style) {
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			c := scr.CellAt(x, y)
+			if c == nil || c.Width == 0 {
+				continue
+			}
+			cell := *c
+			cell.Style.Inherit(style)
+			scr.SetCell(x, y, &cell)
+		}
+	}
+}
+
 // CloneArea clones the given area of the screen and returns a new buffer
 // with the same size as the area. The new buffer will contain the same cells
 // as the area in the screen.
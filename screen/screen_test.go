@@ -542,6 +542,78 @@ func TestFillArea(t *testing.T) {
 	})
 }
 
+func TestFillStyle(t *testing.T) {
+	scr := uv.NewScreenBuffer(4, 2)
+	style := uv.Style{Bg: ansi.Blue}
+	FillStyle(scr, uv.Rect(1, 0, 2, 1), style)
+
+	expected := uv.EmptyCell
+	expected.Style = style
+	for x := range 4 {
+		cell := scr.CellAt(x, 0)
+		if x >= 1 && x < 3 {
+			if !cell.Equal(&expected) {
+				t.Errorf("expected cell %d to be %#v, got %#v", x, expected, cell)
+			}
+		} else if !cell.Equal(&uv.EmptyCell) {
+			t.Errorf("expected cell %d to be empty, got %#v", x, cell)
+		}
+	}
+}
+
+func TestInheritStyle(t *testing.T) {
+	scr := uv.NewScreenBuffer(4, 1)
+	scr.SetCell(0, 0, &uv.Cell{Content: "a", Width: 1, Style: uv.Style{Fg: ansi.Red}})
+	scr.SetCell(1, 0, &uv.Cell{Content: "你", Width: 2})
+	InheritStyle(scr, uv.Rect(0, 0, 3, 1), uv.Style{Fg: ansi.Blue, Bg: ansi.Green, Attrs: uv.AttrBold})
+
+	cases := []struct {
+		x        int
+		expected uv.Style
+	}{
+		{x: 0, expected: uv.Style{Fg: ansi.Red, Bg: ansi.Green, Attrs: uv.AttrBold}},
+		{x: 1, expected: uv.Style{Fg: ansi.Blue, Bg: ansi.Green, Attrs: uv.AttrBold}},
+		{x: 3, expected: uv.Style{}},
+	}
+	for _, tc := range cases {
+		if got := scr.CellAt(tc.x, 0).Style; !got.Equal(&tc.expected) {
+			t.Errorf("expected cell %d style %#v, got %#v", tc.x, tc.expected, got)
+		}
+	}
+	if c := scr.CellAt(1, 0); c.Content != "你" || c.Width != 2 {
+		t.Errorf("expected the wide cell to be kept, got %#v", c)
+	}
+}
+
+func TestFillRune(t *testing.T) {
+	style := uv.Style{Fg: ansi.Red}
+	cases := []struct {
+		name     string
+		r        rune
+		expected string
+	}{
+		{name: "narrow", r: 'x', expected: "xxxxx"},
+		{name: "wide", r: '混', expected: "混混 "},
+		{name: "zero width", r: '\u0301', expected: ""},
+		{name: "control", r: '\n', expected: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scr := uv.NewScreenBuffer(5, 1)
+			FillRune(scr, scr.Bounds(), tc.r, style)
+			if got := scr.String(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+			if tc.expected != "" {
+				if cell := scr.CellAt(0, 0); !cell.Style.Equal(&style) {
+					t.Errorf("expected style %#v, got %#v", style, cell.Style)
+				}
+			}
+		})
+	}
+}
+
 func TestClone(t *testing.T) {
 	t.Run("with Clone method", func(t *testing.T) {
 		scr := &mockScreenWithClone{
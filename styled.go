@@ -3,6 +3,7 @@ package uv
 import (
 	"bytes"
 	"image/color"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/x/ansi"
@@ -21,8 +22,25 @@ type StyledString struct {
 	// Tail is the string that will be appended to the end of the line when the
 	// string is truncated i.e. when [StyledString.Wrap] is false.
 	Tail string
+	// Edge determines what happens to a wide cell that would cross the right
+	// edge of the drawing area. The default is [EdgePolicyPad].
+	Edge EdgePolicy
 }
 
+// EdgePolicy determines how a wide cell that doesn't fit in the remaining
+// columns of an area is handled.
+type EdgePolicy uint8
+
+// Edge policies.
+const (
+	// EdgePolicyPad replaces the wide cell with blank cells, using the same
+	// style, up to the edge of the area.
+	EdgePolicyPad EdgePolicy = iota
+	// EdgePolicySkip drops the wide cell and leaves the remaining columns
+	// untouched.
+	EdgePolicySkip
+)
+
 var _ Drawable = (*StyledString)(nil)
 
 // NewStyledString creates a new [StyledString] for the given method and styled
@@ -42,7 +60,7 @@ func (s *StyledString) String() string {
 
 // Lines returns the styled string decomposed into a slice of [Line]s.
 func (s *StyledString) Lines(m ansi.Method) []Line {
-	return printString(nil, m, 0, 0, Rectangle{}, s.Text, false, "")
+	return printString(nil, m, 0, 0, Rectangle{}, s.Text, false, "", s.Edge)
 }
 
 // Draw renders the styled string to the given buffer at the
@@ -58,7 +76,7 @@ func (s *StyledString) Draw(buf Screen, area Rectangle) {
 	// We need to normalize newlines "\n" to "\r\n" to emulate a raw terminal
 	// output.
 	str = strings.ReplaceAll(str, "\r\n", "\n")
-	printString(buf, buf.WidthMethod(), area.Min.X, area.Min.Y, area, str, !s.Wrap, s.Tail)
+	printString(buf, buf.WidthMethod(), area.Min.X, area.Min.Y, area, str, !s.Wrap, s.Tail, s.Edge)
 }
 
 // Height returns the number of lines in the styled string. This is the number
@@ -81,7 +99,7 @@ func (s *StyledString) WcWidth() int {
 	return w
 }
 
-func (s *StyledString) widthHeight(m ansi.Method) (w, h int) {
+func (s *StyledString) widthHeight(m WidthMethod) (w, h int) {
 	lines := strings.Split(s.Text, "\n")
 	h = len(lines)
 	for _, l := range lines {
@@ -96,6 +114,127 @@ func (s *StyledString) Bounds() Rectangle {
 	return Rect(0, 0, w, h)
 }
 
+// StyledWidth returns the cells width of the widest line in the given string,
+// ignoring any ANSI escape sequences. The string is measured using the given
+// width method, which should be the one of the screen it's drawn on, see
+// [Screen.WidthMethod].
+func StyledWidth(m WidthMethod, s string) int {
+	w, _ := NewStyledString(s).widthHeight(m)
+	return w
+}
+
+// StyledHeight returns the number of lines in the given string. This is the
+// number of lines that the string occupies when drawn on a screen.
+func StyledHeight(s string) int {
+	return NewStyledString(s).Height()
+}
+
+// WrappedLine is a single line of a [StyledString] wrapped or truncated to a
+// given width.
+type WrappedLine struct {
+	// Line is the list of cells of the line. Wide cells are not followed by
+	// placeholder cells.
+	Line Line
+	// Width is the width of the line in cells.
+	Width int
+	// Start is the byte offset in [StyledString.Text] where the line starts.
+	Start int
+	// End is the byte offset in [StyledString.Text] right after the last
+	// byte that makes up the line. It doesn't include the newline ending the
+	// line, nor the part of the line that was truncated.
+	End int
+}
+
+// WrapLines returns the lines of the styled string as they would be drawn in
+// an area of the given width, along with their widths and their offsets in
+// [StyledString.Text].
+//
+// Lines are wrapped when [StyledString.Wrap] is true, and truncated with
+// [StyledString.Tail] otherwise, following the same rules as
+// [StyledString.Draw]. A width of zero or less disables wrapping and
+// truncation. Carriage returns are ignored.
+func (s *StyledString) WrapLines(width int, m ansi.Method) []WrappedLine {
+	p := ansi.GetParser()
+	defer ansi.PutParser(p)
+
+	var tailc Cell
+	if !s.Wrap && width > 0 && len(s.Tail) > 0 {
+		tailc = *NewCell(m, s.Tail)
+	}
+
+	decoder := ansi.DecodeSequenceWc[string]
+	if m == ansi.GraphemeWidth {
+		decoder = ansi.DecodeSequence[string]
+	}
+
+	lines := []WrappedLine{{}}
+	var style Style
+	var link Link
+	var state byte
+	var truncated bool
+	for off := 0; off < len(s.Text); {
+		seq, w, n, newState := decoder(s.Text[off:], state, p)
+		l := &lines[len(lines)-1]
+
+		switch {
+		case w > 0:
+			if truncated {
+				break
+			}
+			cell := Cell{Content: seq, Width: w, Style: style, Link: link}
+			if width > 0 && l.Width+w > width && s.Wrap && l.Width > 0 {
+				// Wrap the line to the given width.
+				lines = append(lines, WrappedLine{Start: off})
+				l = &lines[len(lines)-1]
+			}
+
+			switch {
+			case width > 0 && !s.Wrap && tailc.Width > 0 && l.Width+w > width-tailc.Width:
+				// Truncate the line and append the tail.
+				cell = tailc
+				cell.Style = style
+				cell.Link = link
+				l.Line = append(l.Line, cell)
+				l.Width += tailc.Width
+				truncated = true
+			case width > 0 && !s.Wrap && l.Width+w > width:
+				// The cell would cross the right edge.
+				if s.Edge == EdgePolicyPad {
+					cell.Empty()
+					for ; l.Width < width; l.Width++ {
+						l.Line = append(l.Line, cell)
+					}
+				}
+				truncated = true
+			default:
+				l.Line = append(l.Line, cell)
+				l.Width += w
+				l.End = off + n
+			}
+		case seq == "\n":
+			lines = append(lines, WrappedLine{Start: off + n, End: off + n})
+			truncated = false
+		case seq == "\r":
+			// Ignore carriage returns.
+		default:
+			switch {
+			case ansi.HasCsiPrefix(seq) && p.Command() == 'm':
+				ReadStyle(p.Params(), &style)
+			case ansi.HasOscPrefix(seq) && p.Command() == 8:
+				ReadLink(p.Data(), &link)
+			}
+			if !truncated {
+				l.End = off + n
+			}
+		}
+
+		state = newState
+		off += n
+	}
+
+	return lines
+}
+
 // printString draws a string starting at the given position. If s is nil, it
 // will build and return a slice of [Line]s instead (unwrapped, ignoring bounds).
 func printString[T []byte | string](
@@ -104,6 +243,7 @@ func printString[T []byte | string](
 	x, y int,
 	bounds Rectangle, str T,
 	truncate bool, tail string,
+	edge EdgePolicy,
 ) (lines []Line) {
 	p := ansi.GetParser()
 	defer ansi.PutParser(p)
@@ -126,6 +266,9 @@ func printString[T []byte | string](
 	var style Style
 	var link Link
 	var state byte
+	var linkSeed string      // seed of the id the current link gets if it spans lines
+	var linkCells []Position // cells drawn with the current link before it got an id
+	size := len(str)
 	for len(str) > 0 {
 		seq, width, n, newState := decoder(str, state, p)
 		switch width {
@@ -159,7 +302,37 @@ func printString[T []byte | string](
 						cell.Link = link
 						s.SetCell(x, y, &cell)
 						x += tailc.Width
+					} else if x+cell.Width > bounds.Max.X {
+						// The wide cell would cross the right edge of the
+						// area, never let it overflow.
+						if edge == EdgePolicyPad {
+							cell.Empty()
+							for i := x; i < bounds.Max.X; i++ {
+								s.SetCell(i, y, &cell)
+							}
+						}
+						x += width
 					} else {
+						if linkSeed != "" {
+							if len(linkCells) > 0 && linkCells[0].Y != y {
+								// The link continues onto another line, give
+								// all of its cells an id so that terminals
+								// treat them as a single link.
+								link = link.WithID(NewLinkID(linkSeed))
+								cell.Link = link
+								linkSeed = ""
+								for _, lp := range linkCells {
+									if c := s.CellAt(lp.X, lp.Y); c != nil {
+										lc := *c
+										lc.Link = link
+										s.SetCell(lp.X, lp.Y, &lc)
+									}
+								}
+								linkCells = linkCells[:0]
+							} else {
+								linkCells = append(linkCells, pos)
+							}
+						}
 						// Print the cell to the screen
 						s.SetCell(x, y, &cell)
 						x += width
@@ -179,6 +352,14 @@ func printString[T []byte | string](
 			case ansi.HasOscPrefix(seq) && p.Command() == 8:
 				// Hyperlinks
 				ReadLink(p.Data(), &link)
+				linkSeed, linkCells = "", linkCells[:0]
+				if s != nil && !truncate && link.URL != "" && link.ID() == "" {
+					// Links that continue onto another line need an id so
+					// that terminals treat the segments on each line as a
+					// single link. We derive it from the link offset in the
+					// string to keep it stable across draws.
+					linkSeed = link.URL + "#" + strconv.Itoa(size-len(str))
+				}
 			case ansi.Equal(seq, T("\n")):
 				if s == nil {
 					// When building lines, we need to ensure empty lines are represented.
@@ -327,8 +508,9 @@ func ReadStyle(params ansi.Params, pen *Style) {
 }
 
 // ReadLink reads a hyperlink escape sequence from a data buffer into link.
+// The URI is everything after the params, and can contain semicolons.
 func ReadLink(p []byte, link *Link) {
-	params := bytes.Split(p, []byte{';'})
+	params := bytes.SplitN(p, []byte{';'}, 3)
 	if len(params) != 3 {
 		return
 	}
@@ -2,6 +2,7 @@ package uv
 
 import (
 	"image/color"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/x/ansi"
@@ -441,3 +442,283 @@ func newWcCell(s string, style *Style, link *Link) Cell {
 	}
 	return *c
 }
+
+func TestStyledStringWideCellAtEdge(t *testing.T) {
+	red := &Style{Fg: ansi.Red}
+	cases := []struct {
+		name     string
+		edge     EdgePolicy
+		wrap     bool
+		expected Line
+	}{
+		{
+			name: "pad",
+			edge: EdgePolicyPad,
+			expected: Line{
+				newWcCell("你", red, nil), {},
+				newWcCell("好", red, nil), {},
+				newWcCell("世", red, nil), {},
+				newWcCell(" ", red, nil),
+				newWcCell("x", nil, nil),
+			},
+		},
+		{
+			name: "skip",
+			edge: EdgePolicySkip,
+			expected: Line{
+				newWcCell("你", red, nil), {},
+				newWcCell("好", red, nil), {},
+				newWcCell("世", red, nil), {},
+				EmptyCell,
+				newWcCell("x", nil, nil),
+			},
+		},
+		{
+			name: "pad wrapped last line",
+			edge: EdgePolicyPad,
+			wrap: true,
+			expected: Line{
+				newWcCell("你", red, nil), {},
+				newWcCell("好", red, nil), {},
+				newWcCell("世", red, nil), {},
+				newWcCell(" ", red, nil),
+				newWcCell("x", nil, nil),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := NewScreenBuffer(8, 1)
+			buf.Fill(&Cell{Content: "x", Width: 1})
+			ss := NewStyledString("\x1b[31m你好世界")
+			ss.Wrap = tc.wrap
+			ss.Edge = tc.edge
+			// The area is one column short of fitting the last wide cell.
+			ss.Draw(buf, Rect(0, 0, 7, 1))
+			for x, cell := range buf.Lines[0] {
+				if !cellEqual(&tc.expected[x], &cell) {
+					t.Errorf("expected cell (%d, 0) %#v, got %#v", x, tc.expected[x], cell)
+				}
+			}
+		})
+	}
+}
+
+func TestStyledStringWrappedLinkID(t *testing.T) {
+	input := "go to " + ansi.SetHyperlink("https://charm.sh") + "charm dot sh" + ansi.ResetHyperlink() +
+		" and " + ansi.SetHyperlink("https://example.com", "id=ex") + "example" + ansi.ResetHyperlink() +
+		" " + ansi.SetHyperlink("https://a.b") + "ab" + ansi.ResetHyperlink()
+	ss := NewStyledString(input)
+	ss.Wrap = true
+	buf := NewScreenBuffer(10, 4)
+	ss.Draw(buf, buf.Bounds())
+
+	ids := map[string]string{}
+	for y, line := range buf.Lines {
+		for x, cell := range line {
+			if cell.Link.URL == "" {
+				continue
+			}
+			id := cell.Link.ID()
+			if prev, ok := ids[cell.Link.URL]; ok && prev != id {
+				t.Errorf("expected cell (%d, %d) link id %q, got %q", x, y, prev, id)
+			}
+			ids[cell.Link.URL] = id
+		}
+	}
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 links, got %d", len(ids))
+	}
+	if ids["https://charm.sh"] == "" {
+		t.Error("expected the wrapped link to have an id")
+	}
+	if ids["https://example.com"] != "ex" {
+		t.Errorf("expected existing link id to be kept, got %q", ids["https://example.com"])
+	}
+	if ids["https://a.b"] != "" {
+		t.Errorf("expected no id on a link that fits on one line, got %q", ids["https://a.b"])
+	}
+
+	// Drawing again must produce the same ids.
+	again := NewScreenBuffer(10, 4)
+	ss.Draw(again, again.Bounds())
+	for y, line := range again.Lines {
+		for x, cell := range line {
+			if !cell.Link.Equal(&buf.Lines[y][x].Link) {
+				t.Errorf("expected cell (%d, %d) link %#v, got %#v", x, y, buf.Lines[y][x].Link, cell.Link)
+			}
+		}
+	}
+}
+
+func TestStyledStringLinks(t *testing.T) {
+	input := "a \x1b]8;id=1;https://charm.sh/?q=a;b\x1b\\link\x1b]8;;\x1b\\ \x1b]8;;https://x.com\aalt\x1b]8;;\a!"
+	expected := []struct {
+		content string
+		url     string
+		params  string
+	}{
+		{"a", "", ""},
+		{" ", "", ""},
+		{"l", "https://charm.sh/?q=a;b", "id=1"},
+		{"i", "https://charm.sh/?q=a;b", "id=1"},
+		{"n", "https://charm.sh/?q=a;b", "id=1"},
+		{"k", "https://charm.sh/?q=a;b", "id=1"},
+		{" ", "", ""},
+		{"a", "https://x.com", ""},
+		{"l", "https://x.com", ""},
+		{"t", "https://x.com", ""},
+		{"!", "", ""},
+	}
+
+	ss := NewStyledString(input)
+	buf := NewScreenBuffer(ss.Bounds().Dx(), 1)
+	ss.Draw(buf, buf.Bounds())
+	if buf.Width() != len(expected) {
+		t.Fatalf("expected width %d, got %d", len(expected), buf.Width())
+	}
+	for x, want := range expected {
+		c := buf.CellAt(x, 0)
+		if c.Content != want.content || c.Link.URL != want.url {
+			t.Errorf("expected cell %d %q with link %q, got %q with link %q", x, want.content, want.url, c.Content, c.Link.URL)
+		}
+		if want.params != "" && c.Link.Params != want.params {
+			t.Errorf("expected cell %d link params %q, got %q", x, want.params, c.Link.Params)
+		}
+	}
+
+	// The links round-trip when rendering the buffer.
+	if got := buf.Render(); !strings.Contains(got, "https://charm.sh/?q=a;b") {
+		t.Errorf("expected rendered output to contain the link, got %q", got)
+	}
+}
+
+func TestStyledStringWrapLines(t *testing.T) {
+	type line struct {
+		text       string
+		width      int
+		start, end int
+	}
+	cases := []struct {
+		name     string
+		input    string
+		width    int
+		wrap     bool
+		tail     string
+		expected []line
+	}{
+		{
+			name:  "wrap",
+			input: "hello world\nfoo",
+			width: 6,
+			wrap:  true,
+			expected: []line{
+				{"hello", 6, 0, 6},
+				{"world", 5, 6, 11},
+				{"foo", 3, 12, 15},
+			},
+		},
+		{
+			name:  "wrap wide cells",
+			input: "你好世界",
+			width: 5,
+			wrap:  true,
+			expected: []line{
+				{"你好", 4, 0, 6},
+				{"世界", 4, 6, 12},
+			},
+		},
+		{
+			name:  "wrap with styles",
+			input: "\x1b[31mab\x1b[0mcd",
+			width: 2,
+			wrap:  true,
+			expected: []line{
+				{"ab", 2, 0, 11},
+				{"cd", 2, 11, 13},
+			},
+		},
+		{
+			name:  "truncate",
+			input: "hello world\nfoo",
+			width: 6,
+			expected: []line{
+				{"hello", 6, 0, 6},
+				{"foo", 3, 12, 15},
+			},
+		},
+		{
+			name:  "truncate with tail",
+			input: "hello world",
+			width: 6,
+			tail:  "…",
+			expected: []line{
+				{"hello…", 6, 0, 5},
+			},
+		},
+		{
+			name:  "no width",
+			input: "hello world\r\n\nfoo",
+			width: 0,
+			wrap:  true,
+			expected: []line{
+				{"hello world", 11, 0, 11},
+				{"", 0, 13, 13},
+				{"foo", 3, 14, 17},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ss := NewStyledString(tc.input)
+			ss.Wrap = tc.wrap
+			ss.Tail = tc.tail
+			lines := ss.WrapLines(tc.width, ansi.GraphemeWidth)
+			if len(lines) != len(tc.expected) {
+				t.Fatalf("expected %d lines, got %d: %#v", len(tc.expected), len(lines), lines)
+			}
+			for i, l := range lines {
+				got := line{l.Line.String(), l.Width, l.Start, l.End}
+				if got != tc.expected[i] {
+					t.Errorf("expected line %d %#v, got %#v", i, tc.expected[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestStyledWidthHeight(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		width   int
+		wcwidth int
+		height  int
+	}{
+		{name: "empty", input: "", width: 0, wcwidth: 0, height: 1},
+		{name: "plain", input: "hello", width: 5, wcwidth: 5, height: 1},
+		{name: "styled", input: "\x1b[1;31mhello\x1b[m", width: 5, wcwidth: 5, height: 1},
+		{name: "hyperlink", input: "\x1b]8;;https://example.com\x07link\x1b]8;;\x07", width: 4, wcwidth: 4, height: 1},
+		{name: "wide", input: "你好", width: 4, wcwidth: 4, height: 1},
+		{name: "grapheme", input: "👩‍💻", width: 2, wcwidth: 2, height: 1},
+		{name: "emoji presentation", input: "❤\ufe0f", width: 2, wcwidth: 1, height: 1},
+		{name: "multiline", input: "a\n\x1b[32mlonger\x1b[m\nb", width: 6, wcwidth: 6, height: 3},
+		{name: "crlf", input: "ab\r\nc", width: 2, wcwidth: 2, height: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := StyledWidth(ansi.GraphemeWidth, tc.input); got != tc.width {
+				t.Errorf("expected grapheme width %d, got %d", tc.width, got)
+			}
+			if got := StyledWidth(ansi.WcWidth, tc.input); got != tc.wcwidth {
+				t.Errorf("expected wcwidth %d, got %d", tc.wcwidth, got)
+			}
+			if got := StyledHeight(tc.input); got != tc.height {
+				t.Errorf("expected height %d, got %d", tc.height, got)
+			}
+		})
+	}
+}
@@ -1,11 +1,18 @@
 package uv
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"image/color"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/charmbracelet/x/ansi"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -51,6 +58,25 @@ type Options struct {
 	// This is disabled by default.
 	UseTerminfoKeys bool
 
+	// MaxPasteSize is the maximum size, in bytes, of the text delivered in a
+	// single [PasteEvent]. See [TerminalReader.SetMaxPasteSize].
+	//
+	// If zero, there's no limit.
+	MaxPasteSize int
+
+	// DoubleClickInterval is the maximum duration between two clicks for them
+	// to count as consecutive. See [TerminalReader.SetDoubleClickInterval].
+	//
+	// If zero, [DefaultDoubleClickInterval] is used. If negative, clicks are
+	// never counted as consecutive.
+	DoubleClickInterval time.Duration
+
+	// ResizeDebounce is the duration to wait for the terminal size to settle
+	// before delivering a [WindowSizeEvent]. See [Terminal.SetResizeDebounce].
+	//
+	// If zero, every resize is delivered as soon as it's received.
+	ResizeDebounce time.Duration
+
 	// Logger is an optional logger for tracing terminal I/O operations.
 	// If nil, no logging is performed.
 	Logger Logger
@@ -76,6 +102,28 @@ type Terminal struct {
 	errg  errgroup.Group
 	winch chan os.Signal
 	donec chan struct{}
+
+	// modes keeps track of the terminal modes that were set or reported by
+	// the terminal.
+	modes ansi.Modes
+	// caps is the result of the last [Terminal.Probe] call.
+	caps *Capabilities
+	// inBandResize is whether in-band resize mode was enabled using
+	// [Terminal.EnableInBandResize].
+	inBandResize bool
+	// blurred is whether the terminal reported that it lost focus. See
+	// [Terminal.HasFocus].
+	blurred bool
+	// pasting is whether a bracketed paste is in progress. See
+	// [Terminal.Pasting].
+	pasting bool
+	// pixelc are the pending [Terminal.GetPixelSize] calls waiting for a
+	// [PixelSizeEvent] while the terminal is started.
+	pixelc []chan PixelSizeEvent
+	mu     sync.Mutex // protects modes, caps, inBandResize, blurred, pasting, and pixelc
+
+	// titles is the stack of window titles saved by [Terminal.PushTitle].
+	titles []string
 }
 
 // DefaultTerminal creates a new [Terminal] instance using the default standard
@@ -122,6 +170,7 @@ func NewTerminal(con Console, opts *Options) *Terminal {
 	t.con = con
 	t.opts = opts
 	t.scr = NewTerminalScreen(t.con.Writer(), t.con.Environ())
+	t.modes = ansi.Modes{}
 	t.buf = make([]byte, opts.BufferSize)
 	// These channels never close during the terminal's lifetime.
 	t.evc = make(chan Event)
@@ -156,11 +205,508 @@ func (t *Terminal) GetWinsize() (*Winsize, error) {
 	return ws, nil
 }
 
+// GetPixelSize returns the size of the terminal window in pixels. Combined
+// with the size in cells, this gives the cell size needed to display images.
+//
+// The size is read from the console on Unix-like systems when available.
+// Otherwise, such as on Windows, the terminal is queried using XTWINOPS
+// (CSI 14 t) and GetPixelSize waits up to [DefaultProbeTimeout] for the
+// response. When the terminal is started, the response is also delivered as a
+// [PixelSizeEvent] to the event channel.
+func (t *Terminal) GetPixelSize() (width, height int, err error) {
+	if ws, err := t.con.GetWinsize(); err == nil && ws.Xpixel > 0 && ws.Ypixel > 0 {
+		return int(ws.Xpixel), int(ws.Ypixel), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultProbeTimeout)
+	defer cancel()
+
+	query := ansi.WindowOp(14) //nolint:mnd // report window size in pixels
+	if !t.started() {
+		var size PixelSizeEvent
+		if err := t.query(ctx, query, func(ev Event) bool {
+			size, _ = ev.(PixelSizeEvent)
+			return size.Width > 0 && size.Height > 0
+		}); err != nil {
+			return 0, 0, fmt.Errorf("failed to query pixel size: %w", err)
+		}
+		return size.Width, size.Height, nil
+	}
+
+	pixelc := make(chan PixelSizeEvent, 1)
+	t.mu.Lock()
+	t.pixelc = append(t.pixelc, pixelc)
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.pixelc = slices.DeleteFunc(t.pixelc, func(c chan PixelSizeEvent) bool {
+			return c == pixelc
+		})
+		t.mu.Unlock()
+	}()
+
+	if _, err := t.con.Write([]byte(query)); err != nil {
+		return 0, 0, fmt.Errorf("failed to write pixel size query: %w", err)
+	}
+	select {
+	case size := <-pixelc:
+		return size.Width, size.Height, nil
+	case <-t.donec:
+		return 0, 0, errors.New("failed to query pixel size: terminal stopped")
+	case <-ctx.Done():
+		return 0, 0, fmt.Errorf("failed to query pixel size: %w", ctx.Err())
+	}
+}
+
+// started reports whether the terminal event loop is running.
+func (t *Terminal) started() bool {
+	if t.donec == nil {
+		return false
+	}
+	select {
+	case <-t.donec:
+		return false
+	default:
+		return true
+	}
+}
+
 // Screen returns the terminal's screen.
 func (t *Terminal) Screen() *TerminalScreen {
 	return t.scr
 }
 
+// SetDisambiguateKeys enables or disables the Kitty keyboard
+// [KeyboardEnhancements.DisambiguateEscapeCodes] enhancement while keeping any
+// other enhancements that are already set on the terminal screen.
+//
+// When enabled, keys that share the same legacy encoding, such as Tab and
+// Ctrl+i, Enter and Ctrl+m, and Escape and Ctrl+[, are reported as distinct
+// [KeyPressEvent]s. Terminals that don't support the Kitty keyboard protocol
+// ignore the request and keep sending legacy sequences, in which case these
+// keys are resolved according to [Options.LegacyKeyEncoding].
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) SetDisambiguateKeys(v bool) {
+	var enh KeyboardEnhancements
+	if cur := t.scr.KeyboardEnhancements(); cur != nil {
+		enh = *cur
+	}
+	enh.DisambiguateEscapeCodes = v
+	t.scr.SetKeyboardEnhancements(&enh)
+}
+
+// mouseTrackingModes maps the mouse tracking modes to their DEC modes.
+var mouseTrackingModes = map[MouseMode]ansi.DECMode{
+	MouseModePress:  ansi.ModeMouseX10,
+	MouseModeClick:  ansi.ModeMouseNormal,
+	MouseModeDrag:   ansi.ModeMouseButtonEvent,
+	MouseModeMotion: ansi.ModeMouseAnyEvent,
+}
+
+// SetMouseTracking sets the mouse tracking mode of the terminal. Use
+// [MouseModeNone] to turn mouse tracking off, [MouseModeClick] to receive
+// button presses and releases, [MouseModeDrag] to also receive motion while a
+// button is held, and [MouseModeMotion] to receive all motion.
+//
+// Any previous tracking mode is turned off first, and the SGR mouse encoding
+// (mode 1006) is enabled along with tracking unless another extended
+// encoding is already set, see [TerminalScreen.SetMouseEncoding]. The modes
+// are recorded like [Terminal.WithModes] does, reset when the terminal is
+// stopped, and set again when it's started.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) SetMouseTracking(mode MouseMode) {
+	if cur := t.scr.MouseMode(); mode != MouseModeNone && cur != MouseModeNone && cur != mode {
+		t.scr.SetMouseMode(MouseModeNone)
+	}
+	t.scr.SetMouseMode(mode)
+
+	enc := t.scr.MouseEncoding()
+	switch {
+	case mode == MouseModeNone && enc != MouseEncodingLegacy:
+		t.scr.SetMouseEncoding(MouseEncodingLegacy)
+	case mode != MouseModeNone && enc == MouseEncodingLegacy:
+		t.scr.SetMouseEncoding(MouseEncodingSGR)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for m, dm := range mouseTrackingModes {
+		if m == mode {
+			t.modes[dm] = ansi.ModeSet
+		} else {
+			t.modes[dm] = ansi.ModeReset
+		}
+	}
+	if t.scr.MouseEncoding() == MouseEncodingSGR {
+		t.modes[ansi.ModeMouseExtSgr] = ansi.ModeSet
+	} else {
+		t.modes[ansi.ModeMouseExtSgr] = ansi.ModeReset
+	}
+}
+
+// EnableBracketedPaste enables bracketed paste mode (mode 2004). Pasted text
+// is then delivered as a single [PasteEvent] between a [PasteStartEvent] and
+// a [PasteEndEvent], instead of a stream of key presses. The mode is recorded
+// like [Terminal.WithModes] does, reset when the terminal is stopped, and set
+// again when it's started.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) EnableBracketedPaste() {
+	t.scr.EnableBracketedPaste()
+	t.mu.Lock()
+	t.modes[ansi.ModeBracketedPaste] = ansi.ModeSet
+	t.mu.Unlock()
+}
+
+// DisableBracketedPaste disables bracketed paste mode.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) DisableBracketedPaste() {
+	t.scr.DisableBracketedPaste()
+	t.mu.Lock()
+	t.modes[ansi.ModeBracketedPaste] = ansi.ModeReset
+	t.mu.Unlock()
+}
+
+// Pasting reports whether a bracketed paste is in progress, that is, a
+// [PasteStartEvent] was read and the matching [PasteEndEvent] wasn't yet
+// delivered. This is still true while handling the [PasteEvent] with the
+// pasted text. Applications can use it to suppress key bindings while text is
+// being pasted.
+func (t *Terminal) Pasting() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pasting
+}
+
+// EnableFocusReporting enables focus reporting mode (mode 1004). The terminal
+// then reports a [FocusEvent] when it gains focus and a [BlurEvent] when it
+// loses focus, and [Terminal.HasFocus] reflects the current focus state. The
+// mode is reset when the terminal is stopped and set again when it's started.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) EnableFocusReporting() {
+	t.scr.EnableFocusReporting()
+}
+
+// DisableFocusReporting disables focus reporting mode.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) DisableFocusReporting() {
+	t.scr.DisableFocusReporting()
+}
+
+// HasFocus reports whether the terminal has focus. It's kept up to date from
+// the [FocusEvent] and [BlurEvent] events received by the terminal, see
+// [Terminal.EnableFocusReporting]. The terminal is assumed to have focus
+// until it reports otherwise.
+func (t *Terminal) HasFocus() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.blurred
+}
+
+// EnableInBandResize enables in-band resize mode (mode 2048) and queries the
+// terminal for support. Terminals that support it report size changes as
+// [WindowSizeEvent] and [PixelSizeEvent] through the input stream, which
+// works where SIGWINCH isn't available, such as on Windows, and over
+// connections that don't forward signals.
+//
+// Once the terminal reports the mode as set, SIGWINCH is no longer used to
+// detect size changes. Terminals that don't support the mode ignore it, in
+// which case SIGWINCH keeps being used. The mode is reset when the terminal
+// is stopped and set again when it's started.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) EnableInBandResize() {
+	t.mu.Lock()
+	t.inBandResize = true
+	t.mu.Unlock()
+	t.scr.EnableInBandResize()
+	_, _ = t.scr.WriteString(ansi.RequestModeInBandResize)
+}
+
+// DisableInBandResize disables in-band resize mode and goes back to using
+// SIGWINCH to detect size changes.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) DisableInBandResize() {
+	t.mu.Lock()
+	t.inBandResize = false
+	t.mu.Unlock()
+	t.scr.DisableInBandResize()
+}
+
+// usesInBandResize reports whether in-band resize mode is enabled and the
+// terminal reported it as set.
+func (t *Terminal) usesInBandResize() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inBandResize && t.modes[ansi.ModeInBandResize].IsSet()
+}
+
+// SetCursorShape sets the shape of the terminal cursor and whether it blinks
+// using [ansi.DECSCUSR]. The shape is remembered by the terminal screen, see
+// [TerminalScreen.CursorStyle], and it's reset to the terminal default when
+// the terminal is stopped, and set again when it's started.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) SetCursorShape(shape CursorShape, blink bool) {
+	t.scr.SetCursorStyle(shape, blink)
+}
+
+// SetCursorColor sets the color of the terminal cursor using
+// [ansi.SetCursorColor]. The color is downsampled to the color profile of the
+// terminal screen, see [TerminalScreen.SetColorProfile]. Use nil to reset the
+// cursor color to the terminal default.
+//
+// The color is reset to the terminal default when the terminal is stopped, and
+// set again when it's started.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) SetCursorColor(c color.Color) {
+	if c != nil {
+		c = t.scr.profile.Convert(c)
+	}
+	t.scr.SetCursorColor(c)
+}
+
+// CursorColor returns the cursor color set using [Terminal.SetCursorColor]
+// after downsampling. A nil color indicates the terminal default cursor color.
+func (t *Terminal) CursorColor() color.Color {
+	return t.scr.CursorColor()
+}
+
+// SetProgress sets the state and percentage of the terminal progress bar
+// using [ansi.SetProgressBar] (OSC 9;4). Terminals that support it display the
+// progress in their tab or taskbar. The percentage is clamped between 0 and
+// 100, and it's ignored for the [ProgressBarNone] and
+// [ProgressBarIndeterminate] states. Some terminals display the
+// [ProgressBarWarning] state as paused.
+//
+// The progress bar is cleared when the terminal is stopped, and set again
+// when it's started.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) SetProgress(state ProgressBarState, percent int) {
+	t.scr.SetProgressBar(NewProgressBar(state, percent))
+}
+
+// SetClipboard sets the content of the given clipboard selection, usually
+// [SystemClipboard] or [PrimaryClipboard], using [ansi.SetClipboard] (OSC 52).
+// The content is base64 encoded. An empty content clears the clipboard.
+//
+// Many terminals disable clipboard access by default, or only allow writing
+// to it.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) SetClipboard(selection ClipboardSelection, content string) {
+	_, _ = t.scr.WriteString(ansi.SetClipboard(selection, content))
+}
+
+// RequestClipboard requests the content of the given clipboard selection
+// using [ansi.RequestClipboard] (OSC 52). Terminals that allow reading the
+// clipboard respond with a [ClipboardEvent].
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) RequestClipboard(selection ClipboardSelection) {
+	_, _ = t.scr.WriteString(ansi.RequestClipboard(selection))
+}
+
+// PrependLines inserts the given lines above the screen content in inline
+// mode, pushing them into the terminal scrollback, in a single write. This is
+// useful for loggers that flush many lines at once. Lines can contain SGR
+// escape codes, and the style is reset at the end of each line so that it
+// doesn't leak into the following lines or the screen content.
+//
+// Any pending screen changes are flushed first so that the lines don't
+// interleave with them, and the screen content stays anchored below the
+// inserted lines. See [TerminalScreen.InsertAbove].
+func (t *Terminal) PrependLines(lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	if err := t.scr.Flush(); err != nil {
+		return fmt.Errorf("failed to flush terminal screen: %w", err)
+	}
+
+	var sb strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(line)
+		if strings.IndexByte(line, ansi.ESC) >= 0 {
+			sb.WriteString(ansi.ResetStyle)
+		}
+	}
+	if err := t.scr.InsertAbove(sb.String()); err != nil {
+		return fmt.Errorf("failed to prepend lines: %w", err)
+	}
+	return nil
+}
+
+// SetTitle sets the title of the terminal window. See
+// [TerminalScreen.SetWindowTitle].
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) SetTitle(title string) {
+	t.scr.SetWindowTitle(title)
+}
+
+// PushTitle saves the current window title on a stack and sets a new one. Use
+// [Terminal.PopTitle] to restore the saved title, for example, after
+// temporarily showing progress in the title.
+//
+// The title is saved in the terminal using XTWINOPS (CSI 22 t), and in an
+// in-process stack as a fallback for terminals that don't support it.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) PushTitle(title string) {
+	t.titles = append(t.titles, t.scr.WindowTitle())
+	_, _ = t.scr.WriteString(ansi.WindowOp(22, 2)) //nolint:mnd // save window title
+	t.scr.SetWindowTitle(title)
+}
+
+// PopTitle restores the window title saved by the last [Terminal.PushTitle]
+// call. It does nothing if there's no saved title.
+//
+// The title is restored in the terminal using XTWINOPS (CSI 23 t). When the
+// saved title was set by this terminal, it's also set again explicitly for
+// terminals that don't support the operation. Titles set outside of the
+// program, such as by the shell, can only be restored by the terminal.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) PopTitle() {
+	n := len(t.titles)
+	if n == 0 {
+		return
+	}
+	title := t.titles[n-1]
+	t.titles = t.titles[:n-1]
+	_, _ = t.scr.WriteString(ansi.WindowOp(23, 2)) //nolint:mnd // restore window title
+	if title != "" {
+		t.scr.SetWindowTitle(title)
+	} else {
+		t.scr.windowTitle = ""
+	}
+}
+
+// SetPreserveScreenOnExit sets whether the last rendered frame should remain
+// visible after the terminal is stopped with [Terminal.Stop].
+//
+// See [TerminalScreen.SetPreserveOnExit] for how this interacts with the
+// alternate screen.
+func (t *Terminal) SetPreserveScreenOnExit(v bool) {
+	t.scr.SetPreserveOnExit(v)
+}
+
+// SetCapabilities adds or removes control sequences the renderer uses to
+// update the screen, overriding the ones detected from $TERM and the
+// UV_FORCE_CAPS environment variable. See [TerminalRenderer.SetCapabilities]
+// for the spec format.
+//
+// This is unrelated to the capabilities reported by [Terminal.Probe].
+func (t *Terminal) SetCapabilities(spec string) error {
+	return t.scr.SetCapabilities(spec)
+}
+
+// UseTerminfo sets whether to detect the control sequences used to update the
+// screen from the terminfo database entry for $TERM, falling back to the
+// built-in list of known terminals when there is none. See
+// [TerminalRenderer.SetTerminfoCaps].
+func (t *Terminal) UseTerminfo(v bool) {
+	t.scr.SetTerminfoCaps(v)
+}
+
+// SetDoubleClickInterval sets the maximum duration between two clicks for them
+// to count as consecutive. See [TerminalReader.SetDoubleClickInterval].
+//
+// This must be called before [Terminal.Start] to take effect.
+func (t *Terminal) SetDoubleClickInterval(d time.Duration) {
+	t.opts.DoubleClickInterval = d
+}
+
+// SetResizeDebounce sets the duration to wait for the terminal size to settle
+// before delivering a [WindowSizeEvent]. A burst of resizes, such as the ones
+// emitted while the user drags the terminal window edge, is collapsed into a
+// single event that is sent once no resize has been received for d. The most
+// recent size wins. A zero or negative duration disables debouncing.
+//
+// This must be called before [Terminal.Start] to take effect.
+func (t *Terminal) SetResizeDebounce(d time.Duration) {
+	t.opts.ResizeDebounce = d
+}
+
+// WithModes sets the given terminal modes and returns a function that restores
+// them to their prior recorded state. The modes are restored in reverse order.
+//
+// The prior state of a mode is the last value set using [Terminal.WithModes]
+// or reported by the terminal in a [ModeReportEvent]. Modes with no recorded
+// state are assumed to be reset, and permanently set or reset modes are left
+// untouched.
+//
+// Modes that are managed by the [TerminalScreen], such as the alternate screen
+// and bracketed paste, should be set using their dedicated methods instead to
+// keep the screen state in sync.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (t *Terminal) WithModes(modes ...ansi.Mode) (restore func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev := make([]ansi.ModeSetting, len(modes))
+	for i, m := range modes {
+		prev[i] = t.modes[m]
+		if prev[i].IsPermanentlySet() || prev[i].IsPermanentlyReset() {
+			continue
+		}
+		_, _ = t.scr.WriteString(ansi.SetMode(m))
+		t.modes[m] = ansi.ModeSet
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			for i := len(modes) - 1; i >= 0; i-- {
+				m, v := modes[i], prev[i]
+				switch {
+				case v.IsPermanentlySet() || v.IsPermanentlyReset():
+					continue
+				case v.IsSet():
+					_, _ = t.scr.WriteString(ansi.SetMode(m))
+				default:
+					_, _ = t.scr.WriteString(ansi.ResetMode(m))
+					v = ansi.ModeReset
+				}
+				t.modes[m] = v
+			}
+		})
+	}
+}
+
 // Events returns the terminal's event channel.
 func (t *Terminal) Events() <-chan Event {
 	return t.evc
@@ -176,6 +722,10 @@ func (t *Terminal) Start() error {
 
 	evs := newEventScanner()
 	evs.lookup = t.opts.LookupKeys
+	evs.maxPaste = t.opts.MaxPasteSize
+	if t.opts.DoubleClickInterval != 0 {
+		evs.clickInterval = t.opts.DoubleClickInterval
+	}
 	if evs.lookup {
 		evs.table = buildKeysTable(t.opts.LegacyKeyEncoding, t.con.Getenv("TERM"), t.opts.UseTerminfoKeys)
 	}
@@ -190,14 +740,16 @@ func (t *Terminal) Start() error {
 	}
 
 	// input loop
+	pr := t.pr // Stop clears t.pr while this may still be reading.
 	t.errg.Go(func() error {
 		for {
-			n, err := t.pr.Read(t.buf)
+			n, err := pr.Read(t.buf)
 			if err != nil {
 				return fmt.Errorf("reading terminal input: %w", err)
 			}
+			// Send a copy since the buffer is reused by the next read.
 			select {
-			case bufc <- t.buf[:n]:
+			case bufc <- slices.Clone(t.buf[:n]):
 			case <-t.donec:
 				return nil
 			}
@@ -205,9 +757,52 @@ func (t *Terminal) Start() error {
 	})
 
 	// event loop
+	debounce := t.opts.ResizeDebounce
+	resizec := make(chan WindowSizeEvent)
 	sendEvents := func(buf []byte, expired bool) int {
 		n, events := evs.scanEvents(buf, expired)
 		for _, ev := range events {
+			switch ev := ev.(type) {
+			case ModeReportEvent:
+				t.mu.Lock()
+				t.modes[ev.Mode] = ev.Value
+				t.mu.Unlock()
+			case PasteStartEvent:
+				t.mu.Lock()
+				t.pasting = true
+				t.mu.Unlock()
+			case PasteEndEvent:
+				// Keep reporting the paste until the application received
+				// its end.
+				t.SendEvent(ev)
+				t.mu.Lock()
+				t.pasting = false
+				t.mu.Unlock()
+				continue
+			case FocusEvent, BlurEvent:
+				t.mu.Lock()
+				_, t.blurred = ev.(BlurEvent)
+				t.mu.Unlock()
+			case PixelSizeEvent:
+				t.mu.Lock()
+				for _, c := range t.pixelc {
+					select {
+					case c <- ev:
+					default:
+					}
+				}
+				t.mu.Unlock()
+			case WindowSizeEvent:
+				if debounce > 0 {
+					// In-band resize reports are debounced by the winch
+					// handler.
+					select {
+					case resizec <- ev:
+					case <-t.donec:
+					}
+					continue
+				}
+			}
 			t.SendEvent(ev)
 		}
 		return n
@@ -268,11 +863,54 @@ func (t *Terminal) Start() error {
 	// winch handler
 	NotifyWinch(t.winch)
 	t.errg.Go(func() error {
+		var (
+			timer   *time.Timer
+			timerc  <-chan time.Time
+			pending *WindowSizeEvent
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		settle := func() {
+			if timer == nil {
+				timer = time.NewTimer(debounce)
+			} else {
+				timer.Reset(debounce)
+			}
+			timerc = timer.C
+		}
+
 		for {
 			select {
 			case <-t.donec:
 				return nil
 			case <-t.winch:
+				if t.usesInBandResize() {
+					// Size changes are reported through the input stream.
+					continue
+				}
+				if debounce <= 0 {
+					if err := sendWinsize(); err != nil {
+						return err
+					}
+					continue
+				}
+				// The size is queried once it settles, so drop any
+				// in-band report received before the signal.
+				pending = nil
+				settle()
+			case ev := <-resizec:
+				pending = &ev
+				settle()
+			case <-timerc:
+				timerc = nil
+				if pending != nil {
+					t.SendEvent(*pending)
+					pending = nil
+					continue
+				}
 				if err := sendWinsize(); err != nil {
 					return err
 				}
@@ -321,6 +959,9 @@ func (t *Terminal) Stop() error {
 	if t.winch != nil {
 		signal.Stop(t.winch)
 	}
+	t.mu.Lock()
+	t.pasting = false
+	t.mu.Unlock()
 	if t.pr != nil {
 		t.pr.Cancel()
 		_ = t.pr.Close()
@@ -337,6 +978,42 @@ func (t *Terminal) Stop() error {
 	return nil
 }
 
+// Suspend suspends the program and returns control to the shell, as if the
+// user pressed Ctrl+Z in a cooked terminal. It stops the terminal, restoring
+// its original state, and sends SIGTSTP to the process group. It blocks until
+// the program is resumed with SIGCONT, for example by running fg in the
+// shell.
+//
+// On resume, the terminal is started again, restoring the screen state, and
+// the screen is marked to be fully redrawn on the next render. A
+// [ResumeEvent] and a [WindowSizeEvent] with the current size are then sent
+// to the event channel.
+//
+// On Windows, this returns [ErrPlatformNotSupported] and leaves the terminal
+// untouched.
+func (t *Terminal) Suspend() error {
+	if !canSuspend {
+		return ErrPlatformNotSupported
+	}
+	if err := t.Stop(); err != nil {
+		return err
+	}
+	if err := suspend(); err != nil {
+		_ = t.Start()
+		return fmt.Errorf("failed to suspend process: %w", err)
+	}
+
+	t.scr.rend.Erase()
+	if err := t.Start(); err != nil {
+		return err
+	}
+	t.errg.Go(func() error {
+		t.SendEvent(ResumeEvent{})
+		return nil
+	})
+	return nil
+}
+
 // SendEvent sends an event to the terminal's event channel.
 //
 // This can be used to inject custom events into the terminal's event loop,
@@ -357,6 +1034,30 @@ func (t *Terminal) Write(p []byte) (n int, err error) {
 	return t.con.Write(p)
 }
 
+// WriteRaw writes raw bytes to the terminal's console output, bypassing the
+// screen renderer. This is an escape hatch for sequences the renderer doesn't
+// model, such as custom DCS sequences or experimental graphics protocols.
+//
+// Any pending output of the [TerminalScreen] is flushed before writing the
+// bytes, so they appear in order. Since the bytes might move the cursor, the
+// renderer cursor position is marked as unknown afterwards, and the next
+// [TerminalScreen.Render] or [TerminalScreen.Display] moves the cursor to its
+// absolute position before drawing.
+//
+// In inline mode, the renderer uses relative cursor movements and can only
+// resync the cursor column. Raw output should leave the cursor on the same
+// line in that case.
+func (t *Terminal) WriteRaw(b []byte) error {
+	if err := t.scr.Flush(); err != nil {
+		return fmt.Errorf("failed to flush terminal screen: %w", err)
+	}
+	if _, err := t.con.Write(b); err != nil {
+		return fmt.Errorf("failed to write raw output: %w", err)
+	}
+	t.scr.rend.SetPosition(-1, -1)
+	return nil
+}
+
 // Read reads data from the terminal's console input.
 //
 // This is a low-level operation that bypasses the terminal event processing
@@ -22,6 +22,10 @@ import (
 // ErrReaderNotStarted is returned when the reader has not been started yet.
 var ErrReaderNotStarted = fmt.Errorf("reader not started")
 
+// DefaultDoubleClickInterval is a common maximum duration between two clicks for
+// them to count as a double click. See [TerminalReader.SetDoubleClickInterval].
+const DefaultDoubleClickInterval = 500 * time.Millisecond
+
 // DefaultEscTimeout is the default timeout at which the [TerminalReader] will
 // process ESC sequences. It is set to 50 milliseconds.
 const DefaultEscTimeout = 50 * time.Millisecond
@@ -45,7 +49,8 @@ type TerminalReader struct {
 	// complete and will process the received characters as a complete escape
 	// sequence.
 	//
-	// By default, this is set to [DefaultEscTimeout] (50 milliseconds).
+	// By default, this is set to [DefaultEscTimeout] (50 milliseconds). See
+	// [TerminalReader.SetEscTimeout].
 	EscTimeout time.Duration
 
 	r     io.Reader
@@ -76,6 +81,16 @@ type TerminalReader struct {
 	lastWinsizeX, lastWinsizeY int16 // the last window size for the previous event to prevent multiple size events from firing
 
 	logger Logger // The logger to use for debugging.
+
+	// repeatWindow is the duration within which identical key presses are
+	// coalesced into a single event. Zero disables coalescing.
+	repeatWindow time.Duration
+	repeatKey    *KeyPressEvent   // the pending coalesced key press
+	repeatTimer  *time.Timer      // fires when the pending key press is due
+	repeatc      <-chan time.Time // nil when there's no pending key press
+
+	// filterModifiers indicates whether lone modifier key events are dropped.
+	filterModifiers bool
 }
 
 // NewTerminalReader returns a new input event reader. The reader streams input
@@ -162,12 +177,16 @@ func (d *TerminalReader) StreamEvents(ctx context.Context, eventc chan<- Event)
 		select {
 		case <-ctx.Done():
 			d.sendEvents(eventc, buf.Bytes(), true)
+			d.flushRepeat(eventc)
 			wg.Wait()
 			return nil
 		case err := <-errc:
 			d.sendEvents(eventc, buf.Bytes(), true)
+			d.flushRepeat(eventc)
 			wg.Wait()
 			return err // return the first error encountered
+		case <-d.repeatc:
+			d.flushRepeat(eventc)
 		case <-timeout.C:
 			d.logf("timeout reached")
 
@@ -228,14 +247,148 @@ func (d *TerminalReader) SetLogger(logger Logger) {
 	d.logger = logger
 }
 
+// SetEscTimeout sets how long the reader waits for the rest of an escape
+// sequence before giving up and processing the received bytes as they are.
+// This is what disambiguates a lone Escape key press from the start of an
+// escape sequence. Increase it on slow links, such as SSH over a high latency
+// connection, where sequences might otherwise be split and reported as
+// separate events.
+//
+// A zero or negative duration resets the timeout to [DefaultEscTimeout] (50
+// milliseconds). This must be called before [TerminalReader.StreamEvents].
+func (d *TerminalReader) SetEscTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultEscTimeout
+	}
+	d.EscTimeout = timeout
+}
+
+// SetRepeatCoalesce sets the duration within which rapid repeats of the same
+// key press are coalesced into a single [KeyPressEvent]. The number of
+// coalesced key presses is reported in [Key.RepeatCount].
+//
+// This is useful when a key is being held down, for example, a list or a
+// viewport can scroll by N lines in a single update instead of processing
+// each key press on its own. Note that identical keys typed in quick
+// succession are coalesced as well.
+//
+// A zero or negative window disables coalescing, which is the default, and
+// every key press is delivered as its own event. This must be called before
+// [TerminalReader.StreamEvents].
+func (d *TerminalReader) SetRepeatCoalesce(window time.Duration) {
+	d.repeatWindow = window
+}
+
+// SetFilterModifierKeys sets whether lone modifier key presses and releases,
+// see [Key.IsModifierOnly], are dropped instead of being delivered as events.
+// Modifiers are still reported as part of other keys in [Key.Mod].
+//
+// This is disabled by default. This must be called before
+// [TerminalReader.StreamEvents].
+func (d *TerminalReader) SetFilterModifierKeys(v bool) {
+	d.filterModifiers = v
+}
+
+// SetDoubleClickInterval sets the maximum duration between two clicks for them
+// to count as consecutive. Consecutive clicks of the same button, at most one
+// cell apart, are reported with an increasing [Mouse.Count], up to a triple
+// click, after which counting starts over.
+//
+// The default is [DefaultDoubleClickInterval], which a zero interval restores.
+// A negative interval disables counting consecutive clicks, and every click is
+// then reported with a [Mouse.Count] of 1. This must be called before
+// [TerminalReader.StreamEvents].
+func (d *TerminalReader) SetDoubleClickInterval(interval time.Duration) {
+	if interval == 0 {
+		interval = DefaultDoubleClickInterval
+	}
+	d.eventScanner.clickInterval = interval
+}
+
+// SetMaxPasteSize sets the maximum size, in bytes, of the text delivered in
+// a single [PasteEvent]. Pasted text beyond the limit is discarded as it's
+// read, and the resulting event has [PasteEvent.Truncated] set. This protects
+// applications from unexpectedly large clipboard contents.
+//
+// A zero or negative size means there's no limit, which is the default. This
+// must be called before [TerminalReader.StreamEvents].
+func (d *TerminalReader) SetMaxPasteSize(n int) {
+	d.eventScanner.maxPaste = n
+}
+
 func (d *TerminalReader) sendEvents(eventc chan<- Event, buf []byte, expired bool) int {
 	n, events := d.eventScanner.scanEvents(buf, expired)
 	for _, event := range events {
-		eventc <- event
+		d.sendEvent(eventc, event)
 	}
 	return n
 }
 
+// sendEvent sends the event to the channel, coalescing repeated key presses
+// when enabled.
+func (d *TerminalReader) sendEvent(eventc chan<- Event, event Event) {
+	if d.filterModifiers {
+		switch k := event.(type) {
+		case KeyPressEvent:
+			if Key(k).IsModifierOnly() {
+				return
+			}
+		case KeyReleaseEvent:
+			if Key(k).IsModifierOnly() {
+				return
+			}
+		}
+	}
+
+	if d.repeatWindow <= 0 {
+		eventc <- event
+		return
+	}
+
+	k, ok := event.(KeyPressEvent)
+	if ok && d.repeatKey != nil && sameKey(Key(*d.repeatKey), Key(k)) {
+		d.repeatKey.RepeatCount++
+		d.repeatKey.IsRepeat = d.repeatKey.IsRepeat || k.IsRepeat
+		return
+	}
+
+	d.flushRepeat(eventc)
+	if !ok {
+		eventc <- event
+		return
+	}
+
+	k.RepeatCount = 1
+	d.repeatKey = &k
+	if d.repeatTimer == nil {
+		d.repeatTimer = time.NewTimer(d.repeatWindow)
+	} else {
+		d.repeatTimer.Reset(d.repeatWindow)
+	}
+	d.repeatc = d.repeatTimer.C
+}
+
+// flushRepeat sends the pending coalesced key press, if any.
+func (d *TerminalReader) flushRepeat(eventc chan<- Event) {
+	if d.repeatKey == nil {
+		return
+	}
+	d.repeatTimer.Stop()
+	d.repeatc = nil
+	eventc <- *d.repeatKey
+	d.repeatKey = nil
+}
+
+// sameKey reports whether a and b represent the same key press regardless of
+// their repeat state.
+func sameKey(a, b Key) bool {
+	return a.Code == b.Code &&
+		a.Mod == b.Mod &&
+		a.Text == b.Text &&
+		a.ShiftedCode == b.ShiftedCode &&
+		a.BaseCode == b.BaseCode
+}
+
 // eventScanner scans the buffer for events and sends them to the event channel.
 type eventScanner struct {
 	EventDecoder
@@ -244,14 +397,23 @@ type eventScanner struct {
 	utf16Buf    [2][2]rune // 0 key up, 1 key down
 	graphemeBuf [2][]rune  // 0 key up, 1 key down
 	paste       []byte
+	maxPaste    int  // the maximum paste size, zero means no limit
+	truncated   bool // whether the current paste exceeded maxPaste
 	table       map[string]Key
 	lookup      bool
 	logger      Logger
+
+	// clickInterval is the maximum duration between clicks that are counted
+	// as consecutive. Zero or less disables counting consecutive clicks.
+	clickInterval time.Duration
+	lastClick     Mouse     // the last counted click
+	lastClickTime time.Time // when the last click happened
+	now           func() time.Time
 }
 
 // newEventScanner creates a new event scanner.
 func newEventScanner() *eventScanner {
-	return &eventScanner{}
+	return &eventScanner{clickInterval: DefaultDoubleClickInterval, now: time.Now}
 }
 
 // setLogger sets the logger to use for debugging. If nil, no logging will be
@@ -292,7 +454,7 @@ func (d *eventScanner) scanEvents(buf []byte, expired bool) (total int, events [
 				switch event := event.(type) {
 				case KeyPressEvent:
 					if len(event.Text) > 0 {
-						d.paste = append(d.paste, event.Text...)
+						d.appendPaste(event.Text)
 					} else {
 						seq := string(buf[:n])
 						isWin32 := strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "_")
@@ -302,10 +464,10 @@ func (d *eventScanner) scanEvents(buf []byte, expired bool) (total int, events [
 							// win32-input-mode encodes newlines and other keys
 							// as keypress events. We need to encode them as
 							// their respective values.
-							d.paste = append(d.paste, '\n')
+							d.appendPaste("\n")
 						case isWin32 && unicode.IsControl(event.Code) && event.Code == event.BaseCode:
 							// This handles other cases such as tabs, escapes, etc.
-							d.paste = append(d.paste, string(event.Code)...)
+							d.appendPaste(string(event.Code))
 						case !isWin32:
 							// We ignore all other non-text win32-input-mode events.
 							if esc && n <= 2 && !expired {
@@ -314,7 +476,7 @@ func (d *eventScanner) scanEvents(buf []byte, expired bool) (total int, events [
 								// input.
 								return total, events
 							}
-							d.paste = append(d.paste, seq...)
+							d.appendPaste(seq)
 						}
 					}
 				case UnknownEvent:
@@ -352,6 +514,7 @@ func (d *eventScanner) scanEvents(buf []byte, expired bool) (total int, events [
 			events = append(events, event)
 		case PasteStartEvent:
 			d.paste = []byte{} // reset the paste buffer
+			d.truncated = false
 		case PasteEndEvent:
 			var paste []rune
 			for len(d.paste) > 0 {
@@ -362,7 +525,7 @@ func (d *eventScanner) scanEvents(buf []byte, expired bool) (total int, events [
 				d.paste = d.paste[w:]
 			}
 			d.paste = nil // reset the paste buffer
-			events = append(events, PasteEvent{string(paste)})
+			events = append(events, PasteEvent{Content: string(paste), Truncated: d.truncated})
 		}
 
 		if !isUnknown && event != nil {
@@ -376,7 +539,7 @@ func (d *eventScanner) scanEvents(buf []byte, expired bool) (total int, events [
 				events = append(events, m...)
 			} else {
 				// Otherwise, just append the event to the queue.
-				events = append(events, event)
+				events = append(events, d.countClick(event))
 			}
 		}
 
@@ -387,6 +550,47 @@ func (d *eventScanner) scanEvents(buf []byte, expired bool) (total int, events [
 	return total, events
 }
 
+// maxClickCount is the number of consecutive clicks after which counting
+// starts over.
+const maxClickCount = 3
+
+// countClick sets the click count of mouse click events. Clicks of the same
+// button that happen within the click interval of each other, and at most one
+// cell apart, are consecutive.
+func (d *eventScanner) countClick(event Event) Event {
+	c, ok := event.(MouseClickEvent)
+	if !ok {
+		return event
+	}
+
+	c.Count = 1
+	if d.clickInterval <= 0 {
+		return c
+	}
+
+	now := d.now()
+	last := d.lastClick
+	if last.Count > 0 && last.Count < maxClickCount &&
+		last.Button == c.Button &&
+		abs(last.X-c.X) <= 1 && abs(last.Y-c.Y) <= 1 &&
+		now.Sub(d.lastClickTime) <= d.clickInterval {
+		c.Count = last.Count + 1
+	}
+	d.lastClick = Mouse(c)
+	d.lastClickTime = now
+	return c
+}
+
+// appendPaste appends s to the paste buffer, discarding anything beyond the
+// maximum paste size.
+func (d *eventScanner) appendPaste(s string) {
+	if d.maxPaste > 0 && len(d.paste)+len(s) > d.maxPaste {
+		s = s[:max(0, d.maxPaste-len(d.paste))]
+		d.truncated = true
+	}
+	d.paste = append(d.paste, s...)
+}
+
 func (d *eventScanner) encodeGraphemeBufs() []byte {
 	var b []byte
 	for kd := range d.graphemeBuf {
@@ -3,12 +3,14 @@ package uv
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"hash/maphash"
 	"io"
 	"strings"
 
 	"github.com/charmbracelet/colorprofile"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/xo/terminfo"
 )
 
 // ErrInvalidDimensions is returned when the dimensions of a window are invalid
@@ -50,6 +52,21 @@ const (
 	allCaps              = capVPA | capHPA | capCHA | capCHT | capCBT | capREP | capECH | capICH | capSD | capSU
 )
 
+// capNames maps the names used by [TerminalRenderer.SetCapabilities] to
+// their capabilities.
+var capNames = map[string]capabilities{
+	"VPA": capVPA,
+	"HPA": capHPA,
+	"CHA": capCHA,
+	"CHT": capCHT,
+	"CBT": capCBT,
+	"REP": capREP,
+	"ECH": capECH,
+	"ICH": capICH,
+	"SD":  capSD,
+	"SU":  capSU,
+}
+
 // Set sets the given capabilities.
 func (v *capabilities) Set(c capabilities) {
 	*v |= c
@@ -139,6 +156,8 @@ type TerminalRenderer struct {
 	term             string       // the terminal type
 	clear            bool         // whether to force clear the screen
 	caps             capabilities // terminal control sequence capabilities
+	capsAdd, capsDel capabilities // forced capabilities, see SetCapabilities
+	terminfo         bool         // whether to detect capabilities from terminfo
 	atPhantom        bool         // whether the cursor is out of bounds and at a phantom cell
 	logger           Logger       // The logger used for debugging.
 
@@ -163,7 +182,10 @@ func NewTerminalRenderer(w io.Writer, env []string) (s *TerminalRenderer) {
 	s.profile = colorprofile.Detect(w, env)
 	s.buf = new(bytes.Buffer)
 	s.term = Environ(env).Getenv("TERM")
-	s.caps = xtermCaps(s.term)
+	s.detectCaps()
+	if spec := Environ(env).Getenv("UV_FORCE_CAPS"); spec != "" {
+		_ = s.SetCapabilities(spec)
+	}
 	s.cur = cursor{Cell: EmptyCell, Position: Pos(-1, -1)} // start at -1 to force a move
 	s.saved = s.cur
 	s.oldhash, s.newhash = nil, nil
@@ -202,6 +224,66 @@ func (s *TerminalRenderer) SetMapNewline(v bool) {
 	}
 }
 
+// SetCapabilities adds or removes control sequences from the ones the
+// renderer uses to move the cursor and update the screen. This can be used to
+// work around terminals that misreport their capabilities through $TERM.
+//
+// The spec is a comma-separated list of sequence names prefixed with "+" to
+// add them, or "-" to remove them, for example "+REP,-ICH". Names without a
+// prefix are added. The supported names are VPA, HPA, CHA, CHT, CBT, REP, ECH,
+// ICH, SD, and SU, and are case-insensitive. Unknown names are reported in the
+// returned error, the other changes are still applied.
+//
+// The renderer applies the UV_FORCE_CAPS environment variable the same way
+// when it's created.
+func (s *TerminalRenderer) SetCapabilities(spec string) error {
+	var errs []error
+	for name := range strings.SplitSeq(spec, ",") {
+		name = strings.TrimSpace(name)
+		add := !strings.HasPrefix(name, "-")
+		name = strings.TrimLeft(name, "+-")
+		if name == "" {
+			continue
+		}
+		c, ok := capNames[strings.ToUpper(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown capability %q", name))
+			continue
+		}
+		if add {
+			s.capsAdd.Set(c)
+			s.capsDel.Reset(c)
+		} else {
+			s.capsDel.Set(c)
+			s.capsAdd.Reset(c)
+		}
+	}
+	s.detectCaps()
+	return errors.Join(errs...)
+}
+
+// SetTerminfoCaps sets whether to detect the control sequences supported by
+// the terminal from the terminfo database entry of its terminal type, instead
+// of the built-in list of known terminals. When the entry can't be found, the
+// built-in list is used. Changes made with
+// [TerminalRenderer.SetCapabilities] still apply.
+func (s *TerminalRenderer) SetTerminfoCaps(v bool) {
+	s.terminfo = v
+	s.detectCaps()
+}
+
+// detectCaps computes the capabilities from the terminal type and the forced
+// ones. Capabilities that depend on the tty settings are kept as is.
+func (s *TerminalRenderer) detectCaps() {
+	v := xtermCaps(s.term)
+	if s.terminfo {
+		if ti, _ := terminfo.Load(s.term); ti != nil {
+			v = terminfoCaps(ti, s.term)
+		}
+	}
+	s.caps = v&^s.capsDel | s.capsAdd | s.caps&(capHT|capBS)
+}
+
 // SetBackspace sets whether to use backspace as a movement optimization.
 func (s *TerminalRenderer) SetBackspace(v bool) {
 	if v {
@@ -544,6 +626,12 @@ func (s *TerminalRenderer) updatePen(cell *Cell) {
 		return
 	}
 
+	// Fast path, consecutive cells usually share the same style and link,
+	// especially in fills. There's no need to convert and diff them.
+	if cell.Style.Equal(&s.cur.Style) && cell.Link.Equal(&s.cur.Link) {
+		return
+	}
+
 	// Downsample pen when we don't have a [colorprofile.TrueColor],
 	// otherwise, use the original style.
 	newStyle := ConvertStyle(cell.Style, s.profile)
@@ -557,12 +645,15 @@ func (s *TerminalRenderer) updatePen(cell *Cell) {
 			seq = ansi.ResetStyle
 		}
 		_, _ = s.buf.WriteString(seq)
-		s.cur.Style = cell.Style // Copy the original style
 	}
 	if !newLink.Equal(&oldLink) {
 		_, _ = s.buf.WriteString(ansi.SetHyperlink(newLink.URL, newLink.Params))
-		s.cur.Link = cell.Link // Copy the original link
 	}
+
+	// Copy the original style and link even when they downsample to the
+	// current pen so the next cells with the same style take the fast path.
+	s.cur.Style = cell.Style
+	s.cur.Link = cell.Link
 }
 
 // canClearWith checks whether the given cell can be used by clearing commands
@@ -1177,10 +1268,10 @@ func (s *TerminalRenderer) Render(newbuf *RenderBuffer) {
 		// misbehaves and moves the cursor outside of the scrolling region. For
 		// now, we disable the optimizations completely on Windows.
 		// See https://github.com/microsoft/terminal/issues/19016
-		if s.flags.Contains(tScrollOptim) && s.flags.Contains(tFullscreen) {
-			// Optimize scrolling for the alternate screen buffer.
-			// TODO: Should we optimize for inline mode as well? If so, we need
-			// to know the actual cursor position to use [ansi.DECSTBM].
+		if s.flags.Contains(tScrollOptim) {
+			// Optimize scrolling. In inline mode, see
+			// [TerminalRenderer.scrollInline] for how the lines are scrolled
+			// without touching the rest of the screen.
 			s.scrollOptimize(newbuf)
 		}
 
@@ -1530,13 +1621,53 @@ func moveCursor(s *TerminalRenderer, newbuf *RenderBuffer, x, y int, overwrite b
 	return seq
 }
 
+// terminfoCaps returns the control sequence capabilities defined by the given
+// terminfo entry. Terminfo has no capability for [ansi.CHT], so it's taken
+// from [xtermCaps] for the given terminal type.
+func terminfoCaps(ti *terminfo.Terminfo, termtype string) (v capabilities) {
+	has := func(i int) bool {
+		return len(ti.Strings[i]) > 0
+	}
+	if has(terminfo.RowAddress) {
+		v.Set(capVPA)
+	}
+	if seq := ti.Strings[terminfo.ColumnAddress]; len(seq) > 0 {
+		// Most entries, like xterm, define hpa as CHA.
+		switch seq[len(seq)-1] {
+		case '`':
+			v.Set(capHPA)
+		case 'G':
+			v.Set(capCHA)
+		}
+	}
+	if has(terminfo.BackTab) {
+		v.Set(capCBT)
+	}
+	if has(terminfo.RepeatChar) {
+		v.Set(capREP)
+	}
+	if has(terminfo.EraseChars) {
+		v.Set(capECH)
+	}
+	if has(terminfo.ParmIch) {
+		v.Set(capICH)
+	}
+	if has(terminfo.ParmRindex) {
+		v.Set(capSD)
+	}
+	if has(terminfo.ParmIndex) {
+		v.Set(capSU)
+	}
+	v |= xtermCaps(termtype) & capCHT
+	return v
+}
+
 // xtermCaps returns whether the terminal is xterm-like. This means that the
 // terminal supports ECMA-48 and ANSI X3.64 escape sequences.
 // xtermCaps returns a list of control sequence capabilities for the given
 // terminal type. This only supports a subset of sequences that can
-// be different among terminals.
-// NOTE: A hybrid approach would be to support Terminfo databases for a full
-// set of capabilities.
+// be different among terminals. See [terminfoCaps] for the terminfo based
+// detection.
 func xtermCaps(termtype string) (v capabilities) {
 	parts := strings.Split(termtype, "-")
 	if len(parts) == 0 {
@@ -70,7 +70,9 @@ func (s *TerminalRenderer) scrollOptimize(newbuf *RenderBuffer) {
 // scrolln scrolls the screen up by n lines.
 func (s *TerminalRenderer) scrolln(newbuf *RenderBuffer, n, top, bot, maxY int) (v bool) { //nolint:unparam
 	blank := s.clearBlank()
-	if n > 0 { //nolint:nestif
+	if !s.flags.Contains(tFullscreen) {
+		v = s.scrollInline(newbuf, n, top, bot, blank)
+	} else if n > 0 { //nolint:nestif
 		// Scroll up (forward)
 		v = s.scrollUp(newbuf, n, top, bot, 0, maxY, blank)
 		if !v {
@@ -116,6 +118,37 @@ func (s *TerminalRenderer) scrolln(newbuf *RenderBuffer, n, top, bot, maxY int)
 	return true
 }
 
+// scrollInline scrolls the lines between top and bot by n lines in inline
+// mode. Unlike the alternate screen, the frame doesn't necessarily fill the
+// whole screen, so we can't rely on line feeds and reverse indexes scrolling
+// the frame.
+//
+// When using absolute cursor movements, the frame lines map to the screen
+// lines and we can use [ansi.DECSTBM] to limit the scroll to the given lines.
+// Otherwise, we don't know where the frame is on the screen, and we fall back
+// to deleting and inserting lines which are relative to the cursor.
+func (s *TerminalRenderer) scrollInline(newbuf *RenderBuffer, n, top, bot int, blank *Cell) (v bool) {
+	if s.flags.Contains(tRelativeCursor) {
+		if n > 0 {
+			return s.scrollIdl(newbuf, n, top, bot-n+1, blank)
+		}
+		return s.scrollIdl(newbuf, -n, bot+n+1, top, blank)
+	}
+
+	s.buf.WriteString(ansi.SetTopBottomMargins(top+1, bot+1))
+	s.cur.X, s.cur.Y = -1, -1
+	if n > 0 {
+		v = s.scrollUp(newbuf, n, top, bot, top, bot, blank)
+	} else {
+		v = s.scrollDown(newbuf, -n, top, bot, top, bot, blank)
+	}
+	// Reset the margins to the whole screen.
+	s.buf.WriteString(ansi.SetTopBottomMargins(0, 0))
+	s.cur.X, s.cur.Y = -1, -1
+
+	return v
+}
+
 // scrollBuffer scrolls the buffer by n lines.
 func (s *TerminalRenderer) scrollBuffer(b *RenderBuffer, n, top, bot int, blank *Cell) {
 	if top < 0 || bot < top || bot >= b.Height() {
@@ -2,7 +2,10 @@ package uv
 
 import (
 	"bytes"
+	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestRendererOutput(t *testing.T) {
@@ -153,3 +156,72 @@ var loremIpsum = []string{
 	"Integer sed mi viverra, convallis urna congue, efficitur libero. Duis non eros commodo, ultricies quam hendrerit, molestie velit. Nunc non eros vitae lectus hendrerit gravida. Nunc lacinia neque sapien, et accumsan orci elementum vel. Praesent vel interdum nisl. Duis eget diam turpis. Nunc gravida, lacus dictum congue pharetra, dui est laoreet massa, ac convallis elit est sed dui. Morbi luctus convallis dui id tristique.",
 	"Praesent vitae laoreet risus. Sed ac facilisis justo. Morbi fringilla in est vel volutpat. Aliquam erat tortor, posuere ac libero sit amet, vehicula blandit sapien. Nullam feugiat purus eget sapien bibendum, id posuere risus finibus. Aliquam erat volutpat. Pellentesque ac purus accumsan, accumsan mi vel, viverra lectus. Ut sed porta erat, vitae mollis nibh. Nunc dignissim quis tellus sed blandit. Mauris id velit in odio commodo aliquet.",
 }
+
+func TestRendererInlineScrollOptim(t *testing.T) {
+	if isWindows {
+		t.Skip("scroll optimizations are disabled on Windows")
+	}
+
+	cases := []struct {
+		name     string
+		relative bool
+		scroll   string
+	}{
+		{
+			name:     "relative cursor",
+			relative: true,
+			scroll:   ansi.DeleteLine(1),
+		},
+		{
+			name:   "absolute cursor",
+			scroll: ansi.SetTopBottomMargins(1, 5),
+		},
+	}
+
+	// render draws an append-only log showing the last 5 lines, and returns
+	// the number of bytes written after the first frame.
+	render := func(t *testing.T, relative, optim bool) (int, string) {
+		var buf bytes.Buffer
+		s := NewTerminalRenderer(&buf, []string{"TERM=xterm-256color"})
+		s.SetScrollOptim(optim)
+		s.SetRelativeCursor(relative)
+
+		scr := NewScreenBuffer(10, 5)
+		var n int
+		var last string
+		for i := range 10 {
+			buf.Reset()
+			lines := make([]string, 0, 5)
+			for j := max(0, i-4); j <= i; j++ {
+				lines = append(lines, loremIpsum[0][j*10:(j+1)*10])
+			}
+			scr.Clear()
+			NewStyledString(strings.Join(lines, "\n")).Draw(scr, scr.Bounds())
+			s.Render(scr.RenderBuffer)
+			if err := s.Flush(); err != nil {
+				t.Fatalf("Flush failed: %v", err)
+			}
+			if i >= 5 {
+				n += buf.Len()
+				last = buf.String()
+			}
+		}
+		return n, last
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			without, _ := render(t, c.relative, false)
+			with, last := render(t, c.relative, true)
+			if with >= without {
+				t.Errorf("expected scroll optimization to write fewer bytes, got %d with and %d without", with, without)
+			}
+			if !strings.Contains(last, c.scroll) {
+				t.Errorf("expected %q in output, got %q", c.scroll, last)
+			}
+			if !strings.Contains(last, loremIpsum[0][90:100]) {
+				t.Errorf("expected the appended line in output, got %q", last)
+			}
+		})
+	}
+}
@@ -3,10 +3,12 @@ package uv
 import (
 	"bytes"
 	"image/color"
+	"io"
 	"strings"
 	"testing"
 
 	"github.com/charmbracelet/colorprofile"
+	"github.com/xo/terminfo"
 )
 
 func TestSimpleRendererOutput(t *testing.T) {
@@ -379,6 +381,129 @@ func TestRendererCapabilities(t *testing.T) {
 	}
 }
 
+func TestRendererSetCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      []string
+		spec     string
+		expected capabilities
+		wantErr  bool
+	}{
+		{
+			name:     "add and remove",
+			env:      []string{"TERM=linux"},
+			spec:     "+REP,-ICH",
+			expected: xtermCaps("linux")&^capICH | capREP,
+		},
+		{
+			name:     "no prefix and case",
+			env:      []string{"TERM=dumb"},
+			spec:     " ech , sd",
+			expected: capECH | capSD,
+		},
+		{
+			name:     "unknown name",
+			env:      []string{"TERM=dumb"},
+			spec:     "+FOO,+REP",
+			expected: capREP,
+			wantErr:  true,
+		},
+		{
+			name:     "env override",
+			env:      []string{"TERM=xterm-256color", "UV_FORCE_CAPS=-ECH,-REP"},
+			expected: xtermCaps("xterm-256color") &^ (capECH | capREP),
+		},
+		{
+			name:     "env and spec",
+			env:      []string{"TERM=xterm-256color", "UV_FORCE_CAPS=-ECH"},
+			spec:     "+ECH",
+			expected: xtermCaps("xterm-256color"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := NewTerminalRenderer(&buf, tt.env)
+			if err := r.SetCapabilities(tt.spec); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if r.caps != tt.expected {
+				t.Errorf("expected capabilities %b, got %b", tt.expected, r.caps)
+			}
+		})
+	}
+}
+
+func TestTerminfoCaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		term     string
+		strings  map[int]string
+		expected capabilities
+	}{
+		{
+			name: "xterm",
+			term: "xterm-256color",
+			strings: map[int]string{
+				terminfo.RowAddress:    "\x1b[%i%p1%dd",
+				terminfo.ColumnAddress: "\x1b[%i%p1%dG",
+				terminfo.BackTab:       "\x1b[Z",
+				terminfo.RepeatChar:    "%p1%c\x1b[%p2%{1}%-%db",
+				terminfo.EraseChars:    "\x1b[%p1%dX",
+				terminfo.ParmIch:       "\x1b[%p1%d@",
+				terminfo.ParmRindex:    "\x1b[%p1%dT",
+				terminfo.ParmIndex:     "\x1b[%p1%dS",
+			},
+			expected: capVPA | capCHA | capCBT | capREP | capECH | capICH | capSD | capSU,
+		},
+		{
+			name: "hpa and cht",
+			term: "kitty",
+			strings: map[int]string{
+				terminfo.ColumnAddress: "\x1b[%i%p1%d`",
+			},
+			expected: capHPA | capCHT,
+		},
+		{
+			name:    "empty",
+			term:    "dumb",
+			strings: map[int]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := &terminfo.Terminfo{Strings: map[int][]byte{}}
+			for i, seq := range tt.strings {
+				ti.Strings[i] = []byte(seq)
+			}
+			if got := terminfoCaps(ti, tt.term); got != tt.expected {
+				t.Errorf("expected capabilities %b, got %b", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRendererSetTerminfoCaps(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTerminalRenderer(&buf, []string{"TERM=xterm-256color", "UV_FORCE_CAPS=-ECH"})
+	r.SetBackspace(true)
+	ti, err := terminfo.Load("xterm-256color")
+	if err != nil {
+		t.Skipf("terminfo entry not available: %v", err)
+	}
+
+	r.SetTerminfoCaps(true)
+	if expected := terminfoCaps(ti, "xterm-256color")&^capECH | capBS; r.caps != expected {
+		t.Errorf("expected capabilities %b, got %b", expected, r.caps)
+	}
+	r.SetTerminfoCaps(false)
+	if expected := xtermCaps("xterm-256color")&^capECH | capBS; r.caps != expected {
+		t.Errorf("expected capabilities %b, got %b", expected, r.caps)
+	}
+}
+
 func TestRendererTabStops(t *testing.T) {
 	var buf bytes.Buffer
 	r := NewTerminalRenderer(&buf, []string{"TERM=xterm-256color"})
@@ -1326,6 +1451,47 @@ func TestRendererEnterExitAltScreen(t *testing.T) {
 	}
 }
 
+func BenchmarkRendererGradient(b *testing.B) {
+	const width, height = 200, 60
+
+	// A full-screen gradient made of bands of cells sharing the same
+	// background, like the fills in the space example.
+	cellbuf := NewRenderBuffer(width, height)
+	for y := range height {
+		for x := range width {
+			cellbuf.SetCell(x, y, &Cell{
+				Content: " ",
+				Width:   1,
+				Style: Style{Bg: color.RGBA{
+					R: uint8(x / 10 * 255 / (width / 10)),
+					G: uint8(y * 255 / height),
+					B: 128,
+					A: 255,
+				}},
+			})
+		}
+	}
+
+	for _, profile := range []colorprofile.Profile{
+		colorprofile.TrueColor,
+		colorprofile.ANSI256,
+	} {
+		b.Run(profile.String(), func(b *testing.B) {
+			r := NewTerminalRenderer(io.Discard, []string{"TERM=xterm-256color"})
+			r.SetColorProfile(profile)
+			r.SetFullscreen(true)
+			r.Resize(width, height)
+
+			for b.Loop() {
+				r.Redraw(cellbuf)
+				if err := r.Flush(); err != nil {
+					b.Fatalf("failed to flush renderer: %v", err)
+				}
+			}
+		})
+	}
+}
+
 // Helper type for testing logger
 type testLogger struct {
 	buf *bytes.Buffer
@@ -28,6 +28,8 @@ type TerminalScreen struct {
 	altScreen            bool
 	keyboardEnhancements *KeyboardEnhancements
 	bracketedPaste       bool
+	inBandResize         bool // mode 2048
+	focusReporting       bool // mode 1004
 	mouseMode            MouseMode
 	mouseEncoding        MouseEncoding
 	cursor               *Cursor // initial state is cursor hidden
@@ -37,6 +39,7 @@ type TerminalScreen struct {
 	windowTitle          string
 	syncUpdates          bool // mode 2026
 	resetTabs            bool // DECST8C - reset terminal tabs on start
+	preserveOnExit       bool // keep the last frame visible after reset
 }
 
 var _ Screen = (*TerminalScreen)(nil)
@@ -143,6 +146,18 @@ func (s *TerminalScreen) SetColorProfile(profile colorprofile.Profile) {
 	s.rend.SetColorProfile(profile)
 }
 
+// SetCapabilities adds or removes control sequences used by the renderer. See
+// [TerminalRenderer.SetCapabilities].
+func (s *TerminalScreen) SetCapabilities(spec string) error {
+	return s.rend.SetCapabilities(spec)
+}
+
+// SetTerminfoCaps sets whether the renderer detects the control sequences it
+// uses from the terminfo database. See [TerminalRenderer.SetTerminfoCaps].
+func (s *TerminalScreen) SetTerminfoCaps(v bool) {
+	s.rend.SetTerminfoCaps(v)
+}
+
 // Resize resizes the terminal screen to the specified width and height,
 // updating the render buffer and renderer accordingly.
 func (s *TerminalScreen) Resize(width, height int) {
@@ -467,6 +482,56 @@ func (s *TerminalScreen) BracketedPaste() bool {
 	return s.bracketedPaste
 }
 
+// EnableFocusReporting enables focus reporting mode (mode 1004). When
+// enabled, the terminal reports a [FocusEvent] when it gains focus and a
+// [BlurEvent] when it loses focus.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (s *TerminalScreen) EnableFocusReporting() {
+	s.buf.WriteString(ansi.SetModeFocusEvent)
+	s.focusReporting = true
+}
+
+// DisableFocusReporting disables focus reporting mode.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (s *TerminalScreen) DisableFocusReporting() {
+	s.buf.WriteString(ansi.ResetModeFocusEvent)
+	s.focusReporting = false
+}
+
+// FocusReporting returns whether focus reporting mode is currently enabled.
+func (s *TerminalScreen) FocusReporting() bool {
+	return s.focusReporting
+}
+
+// EnableInBandResize enables in-band resize mode (mode 2048). Terminals that
+// support it report size changes through the input stream, see
+// [WindowSizeEvent] and [PixelSizeEvent], instead of relying on SIGWINCH.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (s *TerminalScreen) EnableInBandResize() {
+	s.buf.WriteString(ansi.SetModeInBandResize)
+	s.inBandResize = true
+}
+
+// DisableInBandResize disables in-band resize mode.
+//
+// The changes can be committed to the underlying writer by calling the
+// [TerminalScreen.Flush] method.
+func (s *TerminalScreen) DisableInBandResize() {
+	s.buf.WriteString(ansi.ResetModeInBandResize)
+	s.inBandResize = false
+}
+
+// InBandResize returns whether in-band resize mode is currently enabled.
+func (s *TerminalScreen) InBandResize() bool {
+	return s.inBandResize
+}
+
 // SetSynchronizedUpdates sets whether to use synchronized updates (mode 2026),
 // which allows applications to batch updates to the terminal screen and flush
 // them all at once for improved performance.
@@ -562,6 +627,19 @@ func (s *TerminalScreen) ProgressBar() *ProgressBar {
 	return s.progressBar
 }
 
+// SetPreserveOnExit sets whether the last rendered frame should remain visible
+// after calling [TerminalScreen.Reset].
+//
+// In inline mode, the frame is already left on the main screen and only the
+// cursor is moved below it. In alternate screen mode, the terminal would
+// normally discard the frame when switching back to the main screen. With
+// this enabled, the last frame is drawn again onto the main screen right
+// after leaving the alternate screen, much like a pager that leaves its
+// output behind.
+func (s *TerminalScreen) SetPreserveOnExit(v bool) {
+	s.preserveOnExit = v
+}
+
 // Reset resets the terminal screen to its default state, clearing the screen,
 // switching back to the main screen buffer if necessary, and resetting all
 // terminal settings to their defaults.
@@ -577,7 +655,17 @@ func (s *TerminalScreen) Reset() {
 		if hasKeyboardEnhancements {
 			sb.WriteString(ansi.KittyKeyboard(0, 1))
 		}
-		sb.WriteString(ansi.ResetModeAltScreenSaveCursor)
+		if s.preserveOnExit {
+			// Switch back to the main screen and draw the last frame there
+			// so that it stays visible after we exit.
+			s.buf.WriteString(sb.String())
+			sb.Reset()
+			s.rend.ExitAltScreen()
+			s.rend.Redraw(s.rbuf)
+			_ = s.rend.Flush()
+		} else {
+			sb.WriteString(ansi.ResetModeAltScreenSaveCursor)
+		}
 	}
 	if hasKeyboardEnhancements {
 		sb.WriteString(ansi.KittyKeyboard(0, 1))
@@ -609,6 +697,12 @@ func (s *TerminalScreen) Reset() {
 	if s.bracketedPaste {
 		sb.WriteString(ansi.ResetModeBracketedPaste)
 	}
+	if s.inBandResize {
+		sb.WriteString(ansi.ResetModeInBandResize)
+	}
+	if s.focusReporting {
+		sb.WriteString(ansi.ResetModeFocusEvent)
+	}
 	if s.windowTitle != "" {
 		sb.WriteString(ansi.SetWindowTitle(""))
 	}
@@ -643,7 +737,16 @@ func (s *TerminalScreen) Restore() {
 		sb.WriteString(ansi.SetTabEvery8Columns)
 	}
 	if s.altScreen {
-		sb.WriteString(ansi.SetModeAltScreenSaveCursor)
+		if !s.rend.flags.Contains(tFullscreen) {
+			// The last frame was copied to the main screen on reset, we need
+			// to switch the renderer back to the alternate screen as well.
+			s.buf.WriteString(sb.String())
+			sb.Reset()
+			s.rend.EnterAltScreen()
+			_ = s.rend.Flush()
+		} else {
+			sb.WriteString(ansi.SetModeAltScreenSaveCursor)
+		}
 	}
 	if s.cursor != nil && !s.cursor.Hidden {
 		sb.WriteString(ansi.ShowCursor)
@@ -677,6 +780,12 @@ func (s *TerminalScreen) Restore() {
 	if s.bracketedPaste {
 		sb.WriteString(ansi.SetModeBracketedPaste)
 	}
+	if s.inBandResize {
+		sb.WriteString(ansi.SetModeInBandResize)
+	}
+	if s.focusReporting {
+		sb.WriteString(ansi.SetModeFocusEvent)
+	}
 	if s.windowTitle != "" {
 		EncodeWindowTitle(&sb, s.windowTitle)
 	}
@@ -751,7 +860,8 @@ func (s *TerminalScreen) InsertAbove(content string) error {
 	for _, line := range lines {
 		lineWidth := s.win.WidthMethod().StringWidth(line)
 		if w > 0 && lineWidth > w {
-			offset += (lineWidth / w)
+			// Lines that are wider than the screen wrap to multiple lines.
+			offset += (lineWidth - 1) / w
 		}
 	}
 
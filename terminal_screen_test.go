@@ -0,0 +1,60 @@
+package uv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTerminalScreenPreserveOnExit(t *testing.T) {
+	cases := []struct {
+		name      string
+		preserve  bool
+		wantFrame bool
+	}{
+		{name: "discard", preserve: false, wantFrame: false},
+		{name: "preserve", preserve: true, wantFrame: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			scr := NewTerminalScreen(&out, nil)
+			scr.Resize(10, 2)
+			scr.EnterAltScreen()
+			NewStyledString("hello").Draw(scr, scr.Bounds())
+			scr.Render()
+			if err := scr.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			out.Reset()
+			scr.SetPreserveOnExit(tc.preserve)
+			scr.Reset()
+			if err := scr.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := out.String()
+			i := strings.Index(got, ansi.ResetModeAltScreenSaveCursor)
+			if i < 0 {
+				t.Fatalf("expected alt screen to be exited, got %q", got)
+			}
+			if hasFrame := strings.Contains(got[i:], "hello"); hasFrame != tc.wantFrame {
+				t.Errorf("expected frame on main screen to be %v, got %q", tc.wantFrame, got)
+			}
+
+			// Restoring should get us back into the alternate screen.
+			out.Reset()
+			scr.Restore()
+			if err := scr.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(out.String(), ansi.SetModeAltScreenSaveCursor) {
+				t.Errorf("expected alt screen to be restored, got %q", out.String())
+			}
+		})
+	}
+}
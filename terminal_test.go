@@ -1,7 +1,17 @@
 package uv
 
 import (
+	"bytes"
+	"image/color"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
 )
 
 func TestSupportsBackspace(t *testing.T) {
@@ -31,3 +41,644 @@ func TestStopIdempotent(t *testing.T) {
 		}
 	}
 }
+
+func TestTerminalSetDisambiguateKeys(t *testing.T) {
+	term := DefaultTerminal()
+	term.Screen().SetKeyboardEnhancements(&KeyboardEnhancements{ReportEventTypes: true})
+	term.SetDisambiguateKeys(true)
+	enh := term.Screen().KeyboardEnhancements()
+	if enh == nil || !enh.DisambiguateEscapeCodes || !enh.ReportEventTypes {
+		t.Fatalf("expected disambiguate and event types enhancements, got %#v", enh)
+	}
+	term.SetDisambiguateKeys(false)
+	enh = term.Screen().KeyboardEnhancements()
+	if enh == nil || enh.DisambiguateEscapeCodes || !enh.ReportEventTypes {
+		t.Fatalf("expected only event types enhancement, got %#v", enh)
+	}
+}
+
+func TestTerminalWithModes(t *testing.T) {
+	term := DefaultTerminal()
+	term.modes[ansi.ModeFocusEvent] = ansi.ModeSet
+	term.modes[ansi.ModeUnicodeCore] = ansi.ModePermanentlySet
+
+	restore := term.WithModes(ansi.ModeMouseButtonEvent, ansi.ModeFocusEvent, ansi.ModeUnicodeCore)
+	want := ansi.SetMode(ansi.ModeMouseButtonEvent) + ansi.SetMode(ansi.ModeFocusEvent)
+	if got := term.scr.buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	term.scr.buf.Reset()
+	restore()
+	restore() // restoring twice is a no-op
+	want = ansi.SetMode(ansi.ModeFocusEvent) + ansi.ResetMode(ansi.ModeMouseButtonEvent)
+	if got := term.scr.buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if v := term.modes[ansi.ModeMouseButtonEvent]; !v.IsReset() {
+		t.Errorf("expected mouse button mode to be reset, got %v", v)
+	}
+}
+
+// testConsole is a [Console] that writes its output to a buffer.
+type testConsole struct {
+	bytes.Buffer
+	in  io.Reader
+	env []string
+}
+
+func (c *testConsole) Close() error                        { return nil }
+func (c *testConsole) Environ() []string                   { return c.env }
+func (c *testConsole) Getenv(key string) string            { return Environ(c.env).Getenv(key) }
+func (c *testConsole) LookupEnv(key string) (string, bool) { return Environ(c.env).LookupEnv(key) }
+func (c *testConsole) Reader() io.Reader                   { return c.in }
+func (c *testConsole) Writer() io.Writer                   { return &c.Buffer }
+func (c *testConsole) MakeRaw() (*term.State, error)       { return nil, nil }
+func (c *testConsole) Restore() error                      { return nil }
+func (c *testConsole) GetSize() (int, int, error)          { return 10, 3, nil }
+func (c *testConsole) GetWinsize() (*Winsize, error)       { return &Winsize{Col: 10, Row: 3}, nil }
+
+func TestTerminalWriteRaw(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	scr := term.Screen()
+	scr.Resize(10, 3)
+	scr.EnterAltScreen()
+	if err := scr.Display(NewStyledString("a")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Queue some output that must be flushed before the raw bytes.
+	scr.SetWindowTitle("title")
+	con.Reset()
+	raw := "\x1bP+q544e\x1b\\"
+	if err := term.WriteRaw([]byte(raw)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := con.String(), ansi.SetWindowTitle("title")+raw; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	con.Reset()
+	if err := scr.Display(NewStyledString("ab")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.HasPrefix(got, ansi.CursorPosition(2, 1)) {
+		t.Errorf("expected next frame to move the cursor to its absolute position, got %q", got)
+	}
+}
+
+func TestTerminalSetCursorShape(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	scr := term.Screen()
+
+	term.SetCursorShape(CursorBar, false)
+	if err := scr.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bar := ansi.SetCursorStyle(CursorBar.Encode(false))
+	if got := con.String(); !strings.Contains(got, bar) {
+		t.Errorf("expected %q in output, got %q", bar, got)
+	}
+	if shape, blink := scr.CursorStyle(); shape != CursorBar || blink {
+		t.Errorf("expected steady bar cursor, got shape %v and blink %v", shape, blink)
+	}
+
+	// The shape is reset on exit and set again on restore.
+	con.Reset()
+	scr.Reset()
+	if err := scr.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.Contains(got, ansi.SetCursorStyle(0)) {
+		t.Errorf("expected cursor style to be reset, got %q", got)
+	}
+	con.Reset()
+	scr.Restore()
+	if err := scr.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.Contains(got, bar) {
+		t.Errorf("expected cursor style to be restored, got %q", got)
+	}
+}
+
+func TestTerminalSetCursorColor(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	scr := term.Screen()
+	scr.SetColorProfile(colorprofile.ANSI256)
+
+	term.SetCursorColor(color.RGBA{R: 0xff, A: 0xff})
+	if err := scr.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ansi.Convert256(color.RGBA{R: 0xff, A: 0xff})
+	if got := term.CursorColor(); got != want {
+		t.Errorf("expected downsampled cursor color %v, got %v", want, got)
+	}
+	if got := con.String(); !strings.Contains(got, ansi.SetCursorColor("#ff0000")) {
+		t.Errorf("expected cursor color to be set, got %q", got)
+	}
+
+	con.Reset()
+	if err := term.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.Contains(got, ansi.ResetCursorColor) {
+		t.Errorf("expected cursor color to be reset on stop, got %q", got)
+	}
+}
+
+func TestTerminalClipboard(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+
+	term.SetClipboard(SystemClipboard, "hello")
+	term.RequestClipboard(PrimaryClipboard)
+	if err := term.Screen().Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "\x1b]52;c;aGVsbG8=\x07" + "\x1b]52;p;?\x07"
+	if got := con.String(); !strings.Contains(got, want) {
+		t.Errorf("expected %q in output, got %q", want, got)
+	}
+
+	// The terminal response is decoded as a clipboard event.
+	var p EventDecoder
+	_, ev := p.Decode([]byte("\x1b]52;p;aGVsbG8=\x07"))
+	if want := (ClipboardEvent{Content: "hello", Selection: PrimaryClipboard}); ev != want {
+		t.Errorf("expected %#v, got %#v", want, ev)
+	}
+}
+
+func TestTerminalSetProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   ProgressBarState
+		percent int
+		want    string
+	}{
+		{name: "normal", state: ProgressBarDefault, percent: 42, want: ansi.SetProgressBar(42)},
+		{name: "clamped", state: ProgressBarError, percent: 150, want: ansi.SetErrorProgressBar(100)},
+		{name: "negative", state: ProgressBarWarning, percent: -5, want: ansi.SetWarningProgressBar(0)},
+		{name: "indeterminate", state: ProgressBarIndeterminate, percent: 50, want: ansi.SetIndeterminateProgressBar},
+		{name: "none", state: ProgressBarNone, want: ansi.ResetProgressBar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			con := &testConsole{env: []string{"TERM=xterm-256color"}}
+			term := NewTerminal(con, nil)
+
+			term.SetProgress(tt.state, tt.percent)
+			if err := term.Screen().Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := con.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("expected %q in output, got %q", tt.want, got)
+			}
+
+			con.Reset()
+			if err := term.Stop(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			cleared := strings.Contains(con.String(), ansi.ResetProgressBar)
+			if want := tt.state != ProgressBarNone; cleared != want {
+				t.Errorf("expected progress bar cleared on stop to be %v, got %q", want, con.String())
+			}
+		})
+	}
+}
+
+func TestTerminalResizeDebounce(t *testing.T) {
+	pr, pw := io.Pipe()
+	con := &testConsole{in: pr, env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	term.SetResizeDebounce(50 * time.Millisecond)
+	if err := term.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer term.Stop() //nolint:errcheck
+
+	go func() {
+		for _, size := range []string{"20;80", "21;81", "22;82"} {
+			_, _ = io.WriteString(pw, "\x1b[48;"+size+";0;0t")
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	var sizes []WindowSizeEvent
+	timeout := time.After(time.Second)
+	for len(sizes) < 2 {
+		select {
+		case ev := <-term.Events():
+			if ws, ok := ev.(WindowSizeEvent); ok {
+				sizes = append(sizes, ws)
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for window size events, got %v", sizes)
+		}
+	}
+
+	// The initial size is sent right away, followed by the last size of the
+	// burst.
+	expected := []WindowSizeEvent{{Width: 10, Height: 3}, {Width: 82, Height: 22}}
+	if !reflect.DeepEqual(sizes, expected) {
+		t.Errorf("expected %v, got %v", expected, sizes)
+	}
+
+	for {
+		select {
+		case ev := <-term.Events():
+			if _, ok := ev.(WindowSizeEvent); ok {
+				t.Errorf("unexpected window size event %v", ev)
+			}
+		case <-time.After(100 * time.Millisecond):
+			return
+		}
+	}
+}
+
+func TestTerminalInBandResize(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+
+	term.EnableInBandResize()
+	want := ansi.SetModeInBandResize + ansi.RequestModeInBandResize
+	if got := term.scr.buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	// SIGWINCH is only skipped once the terminal reports the mode as set.
+	if term.usesInBandResize() {
+		t.Error("expected in-band resize not to be used before the mode report")
+	}
+	term.modes[ansi.ModeInBandResize] = ansi.ModeSet
+	if !term.usesInBandResize() {
+		t.Error("expected in-band resize to be used after the mode report")
+	}
+
+	// The mode is reset on exit and set again on restore.
+	term.scr.buf.Reset()
+	term.scr.Reset()
+	if got := term.scr.buf.String(); !strings.Contains(got, ansi.ResetModeInBandResize) {
+		t.Errorf("expected %q in reset output, got %q", ansi.ResetModeInBandResize, got)
+	}
+	term.scr.buf.Reset()
+	term.scr.Restore()
+	if got := term.scr.buf.String(); !strings.Contains(got, ansi.SetModeInBandResize) {
+		t.Errorf("expected %q in restore output, got %q", ansi.SetModeInBandResize, got)
+	}
+
+	term.DisableInBandResize()
+	if term.usesInBandResize() {
+		t.Error("expected in-band resize not to be used after disabling it")
+	}
+}
+
+// pixelConsole is a [testConsole] that reports the window size in pixels.
+type pixelConsole struct {
+	testConsole
+	ws Winsize
+}
+
+func (c *pixelConsole) GetWinsize() (*Winsize, error) { return &c.ws, nil }
+
+func TestTerminalGetPixelSize(t *testing.T) {
+	t.Run("console", func(t *testing.T) {
+		con := &pixelConsole{ws: Winsize{Col: 10, Row: 3, Xpixel: 100, Ypixel: 60}}
+		term := NewTerminal(con, nil)
+		w, h, err := term.GetPixelSize()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w != 100 || h != 60 {
+			t.Errorf("expected 100x60, got %dx%d", w, h)
+		}
+		if con.Len() != 0 {
+			t.Errorf("expected no query, got %q", con.String())
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		con := &testConsole{in: strings.NewReader("\x1b[4;600;800t"), env: []string{"TERM=xterm-256color"}}
+		term := NewTerminal(con, nil)
+		w, h, err := term.GetPixelSize()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w != 800 || h != 600 {
+			t.Errorf("expected 800x600, got %dx%d", w, h)
+		}
+		if got, want := con.String(), ansi.WindowOp(14); got != want {
+			t.Errorf("expected query %q, got %q", want, got)
+		}
+	})
+
+	t.Run("started", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		con := &testConsole{in: pr, env: []string{"TERM=xterm-256color"}}
+		term := NewTerminal(con, nil)
+		if err := term.Start(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer term.Stop() //nolint:errcheck
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			_, _ = io.WriteString(pw, "\x1b[4;600;800t")
+		}()
+		go func() {
+			for range term.Events() {
+			}
+		}()
+
+		w, h, err := term.GetPixelSize()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w != 800 || h != 600 {
+			t.Errorf("expected 800x600, got %dx%d", w, h)
+		}
+	})
+}
+
+func TestTerminalFocusReporting(t *testing.T) {
+	pr, pw := io.Pipe()
+	con := &testConsole{in: pr, env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	term.EnableFocusReporting()
+	if err := term.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.Contains(got, ansi.SetModeFocusEvent) {
+		t.Errorf("expected %q on start, got %q", ansi.SetModeFocusEvent, got)
+	}
+	if !term.HasFocus() {
+		t.Error("expected the terminal to have focus initially")
+	}
+
+	cases := []struct {
+		input string
+		focus bool
+	}{
+		{input: "\x1b[O", focus: false},
+		{input: "\x1b[I", focus: true},
+	}
+	for _, tc := range cases {
+		go io.WriteString(pw, tc.input) //nolint:errcheck
+		timeout := time.After(time.Second)
+	wait:
+		for {
+			select {
+			case ev := <-term.Events():
+				switch ev.(type) {
+				case FocusEvent, BlurEvent:
+					break wait
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for the focus event of %q", tc.input)
+			}
+		}
+		if got := term.HasFocus(); got != tc.focus {
+			t.Errorf("expected focus %v after %q, got %v", tc.focus, tc.input, got)
+		}
+	}
+
+	con.Reset()
+	if err := term.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.Contains(got, ansi.ResetModeFocusEvent) {
+		t.Errorf("expected %q on stop, got %q", ansi.ResetModeFocusEvent, got)
+	}
+}
+
+func TestTerminalPushPopTitle(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	buf := term.scr.buf
+
+	save, restore := ansi.WindowOp(22, 2), ansi.WindowOp(23, 2)
+	cases := []struct {
+		name     string
+		op       func()
+		expected string
+		title    string
+	}{
+		{
+			name:     "push over external title",
+			op:       func() { term.PushTitle("one") },
+			expected: save + ansi.SetWindowTitle("one"),
+			title:    "one",
+		},
+		{
+			name:     "push over own title",
+			op:       func() { term.PushTitle("two") },
+			expected: save + ansi.SetWindowTitle("two"),
+			title:    "two",
+		},
+		{
+			name:     "pop to own title",
+			op:       term.PopTitle,
+			expected: restore + ansi.SetWindowTitle("one"),
+			title:    "one",
+		},
+		{
+			name:     "pop to external title",
+			op:       term.PopTitle,
+			expected: restore,
+			title:    "",
+		},
+		{
+			name:     "pop empty stack",
+			op:       term.PopTitle,
+			expected: "",
+			title:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		buf.Reset()
+		tc.op()
+		if got := buf.String(); got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, got)
+		}
+		if got := term.scr.WindowTitle(); got != tc.title {
+			t.Errorf("%s: expected title %q, got %q", tc.name, tc.title, got)
+		}
+	}
+}
+
+func TestTerminalPrependLines(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	scr := term.Screen()
+	scr.Resize(10, 1)
+	if err := scr.Display(NewStyledString("frame")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	con.Reset()
+	term.SetTitle("title")
+	lines := []string{"plain", "\x1b[31mred", "0123456789abc"}
+	if err := term.PrependLines(lines); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Pending changes are flushed before the lines, and the wrapped line
+	// takes two lines.
+	got := con.String()
+	if !strings.HasPrefix(got, ansi.SetWindowTitle("title")) {
+		t.Errorf("expected pending changes to be flushed first, got %q", got)
+	}
+	want := ansi.InsertLine(4) +
+		"plain" + ansi.EraseLineRight + "\r\n" +
+		"\x1b[31mred" + ansi.ResetStyle + ansi.EraseLineRight + "\r\n" +
+		"0123456789abc" + ansi.EraseLineRight + "\r\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("expected output to end with %q, got %q", want, got)
+	}
+
+	con.Reset()
+	if err := term.PrependLines(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if con.Len() != 0 {
+		t.Errorf("expected no output, got %q", con.String())
+	}
+}
+
+func TestTerminalSetMouseTracking(t *testing.T) {
+	con := &testConsole{env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	buf := term.scr.buf
+
+	reset := ansi.ResetModeMouseX10 + ansi.ResetModeMouseNormal +
+		ansi.ResetModeMouseButtonEvent + ansi.ResetModeMouseAnyEvent
+	resetEnc := ansi.ResetModeMouseExtSgr + ansi.ResetModeMouseExtUrxvt +
+		ansi.ResetModeMouseExtSgrPixel
+
+	cases := []struct {
+		name     string
+		mode     MouseMode
+		expected string
+		set      ansi.Mode
+	}{
+		{
+			name:     "drag",
+			mode:     MouseModeDrag,
+			expected: ansi.SetModeMouseButtonEvent + ansi.SetModeMouseExtSgr,
+			set:      ansi.ModeMouseButtonEvent,
+		},
+		{
+			name:     "motion",
+			mode:     MouseModeMotion,
+			expected: reset + ansi.SetModeMouseAnyEvent,
+			set:      ansi.ModeMouseAnyEvent,
+		},
+		{
+			name:     "off",
+			mode:     MouseModeNone,
+			expected: reset + resetEnc,
+		},
+	}
+
+	for _, tc := range cases {
+		buf.Reset()
+		term.SetMouseTracking(tc.mode)
+		if got := buf.String(); got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, got)
+		}
+		for _, m := range mouseTrackingModes {
+			if got, want := term.modes[m].IsSet(), m == tc.set; got != want {
+				t.Errorf("%s: expected mode %v set to be %v", tc.name, m, want)
+			}
+		}
+		if got, want := term.modes[ansi.ModeMouseExtSgr].IsSet(), tc.mode != MouseModeNone; got != want {
+			t.Errorf("%s: expected SGR mouse encoding set to be %v", tc.name, want)
+		}
+	}
+
+	// Other extended encodings are kept.
+	term.scr.SetMouseEncoding(MouseEncodingSGRPixel)
+	buf.Reset()
+	term.SetMouseTracking(MouseModeClick)
+	if got, want := buf.String(), ansi.SetModeMouseNormal; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if enc := term.scr.MouseEncoding(); enc != MouseEncodingSGRPixel {
+		t.Errorf("expected SGR pixel encoding to be kept, got %v", enc)
+	}
+}
+
+func TestTerminalBracketedPaste(t *testing.T) {
+	pr, pw := io.Pipe()
+	con := &testConsole{in: pr, env: []string{"TERM=xterm-256color"}}
+	term := NewTerminal(con, nil)
+	term.EnableBracketedPaste()
+	if v := term.modes[ansi.ModeBracketedPaste]; !v.IsSet() {
+		t.Errorf("expected bracketed paste mode to be set, got %v", v)
+	}
+	if err := term.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.Contains(got, ansi.SetModeBracketedPaste) {
+		t.Errorf("expected %q on start, got %q", ansi.SetModeBracketedPaste, got)
+	}
+
+	go io.WriteString(pw, "\x1b[200~hello\x1b[201~") //nolint:errcheck
+	expected := []struct {
+		event   Event
+		pasting bool
+	}{
+		{PasteStartEvent{}, true},
+		{PasteEvent{Content: "hello"}, true},
+		// The state is cleared right after the end is delivered.
+		{PasteEndEvent{}, false},
+	}
+	timeout := time.After(time.Second)
+	for i := 0; i < len(expected); {
+		select {
+		case ev := <-term.Events():
+			if _, ok := ev.(WindowSizeEvent); ok {
+				continue
+			}
+			if !reflect.DeepEqual(ev, expected[i].event) {
+				t.Fatalf("expected event %#v, got %#v", expected[i].event, ev)
+			}
+			if expected[i].pasting && !term.Pasting() {
+				t.Errorf("expected to be pasting on %#v", ev)
+			}
+			i++
+		case <-timeout:
+			t.Fatal("timed out waiting for paste events")
+		}
+	}
+
+	// The paste is done once the next event is read.
+	go io.WriteString(pw, "a") //nolint:errcheck
+	select {
+	case <-term.Events():
+	case <-timeout:
+		t.Fatal("timed out waiting for key event")
+	}
+	if term.Pasting() {
+		t.Error("expected paste to be done")
+	}
+
+	con.Reset()
+	if err := term.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := con.String(); !strings.Contains(got, ansi.ResetModeBracketedPaste) {
+		t.Errorf("expected %q on stop, got %q", ansi.ResetModeBracketedPaste, got)
+	}
+	term.DisableBracketedPaste()
+	if v := term.modes[ansi.ModeBracketedPaste]; !v.IsReset() {
+		t.Errorf("expected bracketed paste mode to be reset, got %v", v)
+	}
+}
@@ -13,6 +13,9 @@ func openTTY() (*os.File, *os.File, error) {
 	return nil, nil, ErrPlatformNotSupported
 }
 
+// canSuspend reports whether the platform supports [Suspend].
+const canSuspend = false
+
 func suspend() error {
 	return ErrPlatformNotSupported
 }
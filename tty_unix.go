@@ -20,6 +20,9 @@ func openTTY() (inTty, outTty *os.File, err error) {
 	return f, f, nil
 }
 
+// canSuspend reports whether the platform supports [Suspend].
+const canSuspend = true
+
 func suspend() (err error) {
 	// Send SIGTSTP to the entire process group.
 	c := make(chan os.Signal, 1)
@@ -24,6 +24,9 @@ func openTTY() (inTty, outTty *os.File, err error) {
 	return inTty, outTty, nil
 }
 
+// canSuspend reports whether the platform supports [Suspend].
+const canSuspend = false
+
 func suspend() (err error) {
 	// On Windows, suspending the process group is not supported in the same
 	// way as Unix-like systems.
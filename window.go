@@ -1,6 +1,10 @@
 package uv
 
-import "github.com/charmbracelet/x/ansi"
+import (
+	"slices"
+
+	"github.com/charmbracelet/x/ansi"
+)
 
 // Window represents a rectangular area on the screen. It can be a root window
 // with no parent, or a sub-window with a parent window. A window can have its
@@ -8,9 +12,12 @@ import "github.com/charmbracelet/x/ansi"
 type Window struct {
 	*Buffer
 
-	method *WidthMethod
-	parent *Window
-	bounds Rectangle
+	method   *WidthMethod
+	parent   *Window
+	bounds   Rectangle
+	view     bool
+	z        int
+	children []*Window
 }
 
 var (
@@ -30,6 +37,101 @@ func (w *Window) Parent() *Window {
 	return w.parent
 }
 
+// Children returns the child windows and views of the window in z order, from
+// the bottom-most to the top-most. Children with the same z-index are ordered
+// by creation.
+func (w *Window) Children() []*Window {
+	children := slices.Clone(w.children)
+	slices.SortStableFunc(children, func(a, b *Window) int {
+		return a.z - b.z
+	})
+	return children
+}
+
+// WindowAt returns the top-most child window or view that contains the given
+// position, relative to the window, or nil if there is none. Only direct
+// children are considered.
+//
+// It can be used to route mouse events to the window under the pointer.
+func (w *Window) WindowAt(p Position) *Window {
+	children := w.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		if p.In(children[i].bounds) {
+			return children[i]
+		}
+	}
+	return nil
+}
+
+// ZIndex returns the z-index of the window. Windows with a higher z-index are
+// drawn on top of their siblings with a lower z-index.
+func (w *Window) ZIndex() int {
+	return w.z
+}
+
+// SetZIndex sets the z-index of the window. Windows with a higher z-index are
+// drawn on top of their siblings with a lower z-index.
+func (w *Window) SetZIndex(z int) {
+	w.z = z
+}
+
+// BringToFront raises the z-index of the window above all of its siblings.
+func (w *Window) BringToFront() {
+	if w.parent == nil {
+		return
+	}
+	for _, c := range w.parent.children {
+		if c != w && c.z >= w.z {
+			w.z = c.z + 1
+		}
+	}
+}
+
+// SendToBack lowers the z-index of the window below all of its siblings.
+func (w *Window) SendToBack() {
+	if w.parent == nil {
+		return
+	}
+	for _, c := range w.parent.children {
+		if c != w && c.z <= w.z {
+			w.z = c.z - 1
+		}
+	}
+}
+
+// Remove removes the window from its parent window. The window is no longer
+// drawn with its parent, and it becomes a root window.
+func (w *Window) Remove() {
+	if w.parent == nil {
+		return
+	}
+	w.parent.children = slices.DeleteFunc(w.parent.children, func(c *Window) bool {
+		return c == w
+	})
+	w.parent = nil
+}
+
+// Draw draws the window buffer to the given screen at the specified area,
+// followed by its child windows in z order. Child windows are drawn at their
+// position relative to the area and clipped to it. Views share the buffer of
+// their parent window, so they are not drawn again.
+//
+// It implements the [Drawable] interface.
+func (w *Window) Draw(scr Screen, area Rectangle) {
+	w.Buffer.Draw(scr, area)
+	if len(w.children) == 0 || area.Empty() {
+		return
+	}
+
+	clip := &clipScreen{Screen: scr, clip: area}
+	for _, c := range w.Children() {
+		if c.view {
+			continue
+		}
+		c.Draw(clip, c.bounds.Add(area.Min))
+	}
+}
+
 // MoveTo moves the window to the specified x and y coordinates.
 func (w *Window) MoveTo(x, y int) {
 	size := w.bounds.Size()
@@ -143,5 +245,9 @@ func newWindow(parent *Window, x, y, width, height int, method *WidthMethod, vie
 	w.parent = parent
 	w.method = method
 	w.bounds = Rect(x, y, width, height)
+	w.view = view
+	if parent != nil {
+		parent.children = append(parent.children, w)
+	}
 	return w
 }
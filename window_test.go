@@ -0,0 +1,129 @@
+package uv
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWindowZOrder(t *testing.T) {
+	root := NewWindow(4, 1, nil)
+	a := root.NewWindow(0, 0, 2, 1)
+	b := root.NewWindow(1, 0, 2, 1)
+	c := root.NewWindow(2, 0, 2, 1)
+	a.Fill(&Cell{Content: "a", Width: 1})
+	b.Fill(&Cell{Content: "b", Width: 1})
+	c.Fill(&Cell{Content: "c", Width: 1})
+
+	draw := func() string {
+		scr := NewScreenBuffer(4, 1)
+		root.Draw(scr, scr.Bounds())
+		return scr.String()
+	}
+
+	cases := []struct {
+		name     string
+		op       func()
+		order    []*Window
+		expected string
+	}{
+		{
+			name:     "creation order",
+			op:       func() {},
+			order:    []*Window{a, b, c},
+			expected: "abcc",
+		},
+		{
+			name:     "bring to front",
+			op:       a.BringToFront,
+			order:    []*Window{b, c, a},
+			expected: "aacc",
+		},
+		{
+			name:     "send to back",
+			op:       c.SendToBack,
+			order:    []*Window{c, b, a},
+			expected: "aabc",
+		},
+		{
+			name:     "set z-index",
+			op:       func() { b.SetZIndex(a.ZIndex() + 1) },
+			order:    []*Window{c, a, b},
+			expected: "abbc",
+		},
+		{
+			name:     "remove",
+			op:       b.Remove,
+			order:    []*Window{c, a},
+			expected: "aacc",
+		},
+	}
+
+	for _, tc := range cases {
+		tc.op()
+		if got := root.Children(); !slices.Equal(tc.order, got) {
+			t.Errorf("%s: expected children %v, got %v", tc.name, tc.order, got)
+		}
+		if got := draw(); got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, got)
+		}
+	}
+
+	if b.HasParent() {
+		t.Error("expected removed window to have no parent")
+	}
+}
+
+func TestWindowDrawChildren(t *testing.T) {
+	root := NewWindow(4, 2, nil)
+	root.Fill(&Cell{Content: ".", Width: 1})
+
+	// Children are drawn relative to the area and clipped to it.
+	child := root.NewWindow(2, 1, 3, 1)
+	child.Fill(&Cell{Content: "x", Width: 1})
+	grandchild := child.NewWindow(1, 0, 1, 1)
+	grandchild.Fill(&Cell{Content: "y", Width: 1})
+
+	// Views share their parent buffer and are not drawn again.
+	view := root.NewView(0, 0, 1, 1)
+	view.SetCell(0, 0, &Cell{Content: "v", Width: 1})
+
+	scr := NewScreenBuffer(6, 4)
+	root.Draw(scr, Rect(1, 1, 4, 2))
+	expected := "\n v...\n ..xy\n"
+	if got := scr.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestWindowAt(t *testing.T) {
+	root := NewWindow(10, 10, nil)
+	a := root.NewWindow(0, 0, 5, 5)
+	b := root.NewWindow(3, 3, 5, 5)
+	view := root.NewView(8, 8, 2, 2)
+	a.NewWindow(0, 0, 1, 1) // grandchildren are not considered
+
+	cases := []struct {
+		name     string
+		pos      Position
+		expected *Window
+	}{
+		{"only a", Pos(1, 1), a},
+		{"overlap", Pos(4, 4), b},
+		{"only b", Pos(7, 7), b},
+		{"view", Pos(9, 9), view},
+		{"grandchild", Pos(0, 0), a},
+		{"none", Pos(9, 0), nil},
+		{"outside", Pos(-1, -1), nil},
+	}
+
+	for _, tc := range cases {
+		if got := root.WindowAt(tc.pos); got != tc.expected {
+			t.Errorf("%s: expected window %p at %v, got %p", tc.name, tc.expected, tc.pos, got)
+		}
+	}
+
+	a.BringToFront()
+	if got := root.WindowAt(Pos(4, 4)); got != a {
+		t.Errorf("expected window %p on top after bringing it to front, got %p", a, got)
+	}
+}